@@ -13,11 +13,18 @@ const DefaultSystemPrompt = "You are a helpful assistant responding via LINE mes
 
 // Config struct
 type Config struct {
-	App      `mapstructure:"app"`
-	Postgres `mapstructure:"postgres"`
-	Line     `mapstructure:"line"`
-	LMStudio `mapstructure:"lmstudio"`
-	Session  `mapstructure:"session"`
+	App           `mapstructure:"app"`
+	Postgres      `mapstructure:"postgres"`
+	Redis         `mapstructure:"redis"`
+	Line          `mapstructure:"line"`
+	LMStudio      `mapstructure:"lmstudio"`
+	Session       `mapstructure:"session"`
+	Webhook       `mapstructure:"webhook"`
+	RateLimit     `mapstructure:"rate_limit"`
+	Transcription `mapstructure:"transcription"`
+	Sticker       `mapstructure:"sticker"`
+	Admin         `mapstructure:"admin"`
+	Persona       `mapstructure:"persona"`
 }
 
 // App struct
@@ -37,6 +44,13 @@ type Postgres struct {
 	SSLMode  bool   `mapstructure:"sslmode"`
 }
 
+// Redis struct - Configuration for the Redis (RESP) connection
+type Redis struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
 // Line struct
 type Line struct {
 	ChannelSecret string `mapstructure:"channel_secret"`
@@ -45,16 +59,85 @@ type Line struct {
 
 // LMStudio struct - Configuration for LM Studio client
 type LMStudio struct {
+	Provider     string `mapstructure:"provider"` // "lmstudio" (default) or "ollama" for Ollama's native API
 	BaseURL      string `mapstructure:"base_url"`
 	Model        string `mapstructure:"model"`
 	Timeout      int    `mapstructure:"timeout"`
 	SystemPrompt string `mapstructure:"system_prompt"`
+	Stream       bool   `mapstructure:"stream"`
+	Vision       bool   `mapstructure:"vision"` // Model accepts images; enables replies to image messages
+	Tools        bool   `mapstructure:"tools"`  // Model supports function calling; offers the todo tools
+	// Token budget used to trim conversation history
+	ContextTokens      int    `mapstructure:"context_tokens"`
+	ReplyTokens        int    `mapstructure:"reply_tokens"`
+	ModelContextTokens string `mapstructure:"model_context_tokens"` // e.g. "qwen2.5-7b-instruct=32768,llama-3.2-3b-instruct=8192"
+	// Several OpenAI-compatible servers behind one client; BaseURL and Model are ignored when set
+	Backends            string `mapstructure:"backends"`              // JSON array, e.g. [{"name":"lmstudio","base_url":"http://localhost:1234","weight":2}]
+	HealthCheckInterval int    `mapstructure:"health_check_interval"` // Seconds between backend health checks
+	// Circuit breaker that fails requests fast while the server is down
+	CircuitBreakerThreshold int `mapstructure:"circuit_breaker_threshold"` // Consecutive failures that open the circuit
+	CircuitBreakerCooldown  int `mapstructure:"circuit_breaker_cooldown"`  // Seconds the circuit stays open before a probe
+	// Retry policy for transient failures
+	Retry LMStudioRetry `mapstructure:"retry"`
+}
+
+// LMStudioRetry struct - Retry policy for LLM requests; zero values use the defaults
+type LMStudioRetry struct {
+	MaxAttempts    int     `mapstructure:"max_attempts"`     // Attempts per request, including the first
+	InitialDelayMs int     `mapstructure:"initial_delay_ms"` // Milliseconds of the first backoff delay
+	MaxDelay       int     `mapstructure:"max_delay"`        // Seconds of the largest backoff delay
+	Multiplier     float64 `mapstructure:"multiplier"`       // Backoff growth per attempt
+	Budget         int     `mapstructure:"budget"`           // Seconds all attempts of a request may take
 }
 
 // Session struct - Configuration for user session management
 type Session struct {
-	Timeout  int `mapstructure:"timeout"`
-	MaxTurns int `mapstructure:"max_turns"`
+	Timeout         int    `mapstructure:"timeout"`
+	MaxTurns        int    `mapstructure:"max_turns"`
+	Driver          string `mapstructure:"driver"`
+	MaxSessions     int    `mapstructure:"max_sessions"`
+	CleanupInterval int    `mapstructure:"cleanup_interval"`
+	Summarize       bool   `mapstructure:"summarize"`
+}
+
+// Webhook struct - Configuration for asynchronous webhook processing
+type Webhook struct {
+	Workers     int    `mapstructure:"workers"`
+	QueueSize   int    `mapstructure:"queue_size"`
+	DedupDriver string `mapstructure:"dedup_driver"`
+	DedupTTL    int    `mapstructure:"dedup_ttl"`
+}
+
+// RateLimit struct - Configuration for throttling LLM-backed replies
+type RateLimit struct {
+	UserPerMinute int `mapstructure:"user_per_minute"`
+	UserBurst     int `mapstructure:"user_burst"`
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+	QueueTimeout  int `mapstructure:"queue_timeout"`
+}
+
+// Transcription struct - Configuration for the OpenAI-compatible speech-to-text server
+type Transcription struct {
+	BaseURL  string `mapstructure:"base_url"` // Audio messages are ignored when empty
+	Model    string `mapstructure:"model"`
+	Language string `mapstructure:"language"`
+	Timeout  int    `mapstructure:"timeout"`
+}
+
+// Sticker struct - Configuration for answering incoming stickers
+type Sticker struct {
+	Policy  string `mapstructure:"policy"`  // ignore, llm, or reply
+	Replies string `mapstructure:"replies"` // e.g. "thanks=11537:52002734,*=11537:52002736"
+}
+
+// Admin struct - Configuration for the admin-only LINE commands
+type Admin struct {
+	UserIDs string `mapstructure:"user_ids"` // Comma-separated LINE user IDs, e.g. "U1234...,U5678..."
+}
+
+// Persona struct - Configuration for the personas users can pick with /persona
+type Persona struct {
+	Catalog string `mapstructure:"catalog"` // JSON object of name to system prompt, e.g. {"tutor":"You are a patient tutor."}
 }
 
 var config Config
@@ -17,15 +17,47 @@ func setupTestEnv() {
 	os.Setenv("POSTGRES_PASSWORD", "test")
 	os.Setenv("POSTGRES_DATABASE", "test")
 	os.Setenv("POSTGRES_SSLMODE", "false")
+	os.Setenv("REDIS_ADDR", "localhost:6379")
+	os.Setenv("REDIS_PASSWORD", "test")
+	os.Setenv("REDIS_DB", "0")
 	os.Setenv("LINE_CHANNEL_SECRET", "test")
 	os.Setenv("LINE_CHANNEL_TOKEN", "test")
+	os.Setenv("LMSTUDIO_PROVIDER", "")
 	os.Setenv("LMSTUDIO_BASE_URL", "http://localhost:1234")
 	os.Setenv("LMSTUDIO_MODEL", "test-model")
 	os.Setenv("LMSTUDIO_TIMEOUT", "30")
 	os.Setenv("LMSTUDIO_SYSTEM_PROMPT", "test prompt")
+	os.Setenv("LMSTUDIO_STREAM", "false")
+	os.Setenv("LMSTUDIO_VISION", "false")
+	os.Setenv("LMSTUDIO_TOOLS", "false")
+	os.Setenv("LMSTUDIO_CONTEXT_TOKENS", "0")
+	os.Setenv("LMSTUDIO_REPLY_TOKENS", "0")
+	os.Setenv("LMSTUDIO_MODEL_CONTEXT_TOKENS", "")
+	os.Setenv("LMSTUDIO_BACKENDS", "")
+	os.Setenv("LMSTUDIO_HEALTH_CHECK_INTERVAL", "0")
+	os.Setenv("LMSTUDIO_CIRCUIT_BREAKER_THRESHOLD", "0")
+	os.Setenv("LMSTUDIO_CIRCUIT_BREAKER_COOLDOWN", "0")
+	os.Setenv("LMSTUDIO_RETRY_MAX_ATTEMPTS", "0")
+	os.Setenv("LMSTUDIO_RETRY_INITIAL_DELAY_MS", "0")
+	os.Setenv("LMSTUDIO_RETRY_MAX_DELAY", "0")
+	os.Setenv("LMSTUDIO_RETRY_MULTIPLIER", "0")
+	os.Setenv("LMSTUDIO_RETRY_BUDGET", "0")
 	// Session defaults - set to 0 to simulate application layer applying defaults
 	os.Setenv("SESSION_TIMEOUT", "0")
 	os.Setenv("SESSION_MAX_TURNS", "0")
+	os.Setenv("SESSION_DRIVER", "memory")
+	os.Setenv("SESSION_MAX_SESSIONS", "0")
+	os.Setenv("SESSION_CLEANUP_INTERVAL", "0")
+	os.Setenv("SESSION_SUMMARIZE", "false")
+	os.Setenv("WEBHOOK_WORKERS", "0")
+	os.Setenv("WEBHOOK_QUEUE_SIZE", "0")
+	os.Setenv("WEBHOOK_DEDUP_DRIVER", "memory")
+	os.Setenv("WEBHOOK_DEDUP_TTL", "0")
+	os.Setenv("RATE_LIMIT_USER_PER_MINUTE", "0")
+	os.Setenv("RATE_LIMIT_USER_BURST", "0")
+	os.Setenv("RATE_LIMIT_MAX_CONCURRENT", "0")
+	os.Setenv("RATE_LIMIT_QUEUE_TIMEOUT", "0")
+	os.Setenv("TRANSCRIPTION_TIMEOUT", "0")
 }
 
 // cleanupTestEnv cleans up environment variables after tests
@@ -39,14 +71,46 @@ func cleanupTestEnv() {
 	os.Unsetenv("POSTGRES_PASSWORD")
 	os.Unsetenv("POSTGRES_DATABASE")
 	os.Unsetenv("POSTGRES_SSLMODE")
+	os.Unsetenv("REDIS_ADDR")
+	os.Unsetenv("REDIS_PASSWORD")
+	os.Unsetenv("REDIS_DB")
 	os.Unsetenv("LINE_CHANNEL_SECRET")
 	os.Unsetenv("LINE_CHANNEL_TOKEN")
+	os.Unsetenv("LMSTUDIO_PROVIDER")
 	os.Unsetenv("LMSTUDIO_BASE_URL")
 	os.Unsetenv("LMSTUDIO_MODEL")
 	os.Unsetenv("LMSTUDIO_TIMEOUT")
 	os.Unsetenv("LMSTUDIO_SYSTEM_PROMPT")
+	os.Unsetenv("LMSTUDIO_STREAM")
+	os.Unsetenv("LMSTUDIO_VISION")
+	os.Unsetenv("LMSTUDIO_TOOLS")
+	os.Unsetenv("LMSTUDIO_CONTEXT_TOKENS")
+	os.Unsetenv("LMSTUDIO_REPLY_TOKENS")
+	os.Unsetenv("LMSTUDIO_MODEL_CONTEXT_TOKENS")
+	os.Unsetenv("LMSTUDIO_BACKENDS")
+	os.Unsetenv("LMSTUDIO_HEALTH_CHECK_INTERVAL")
+	os.Unsetenv("LMSTUDIO_CIRCUIT_BREAKER_THRESHOLD")
+	os.Unsetenv("LMSTUDIO_CIRCUIT_BREAKER_COOLDOWN")
+	os.Unsetenv("LMSTUDIO_RETRY_MAX_ATTEMPTS")
+	os.Unsetenv("LMSTUDIO_RETRY_INITIAL_DELAY_MS")
+	os.Unsetenv("LMSTUDIO_RETRY_MAX_DELAY")
+	os.Unsetenv("LMSTUDIO_RETRY_MULTIPLIER")
+	os.Unsetenv("LMSTUDIO_RETRY_BUDGET")
 	os.Unsetenv("SESSION_TIMEOUT")
 	os.Unsetenv("SESSION_MAX_TURNS")
+	os.Unsetenv("SESSION_DRIVER")
+	os.Unsetenv("SESSION_MAX_SESSIONS")
+	os.Unsetenv("SESSION_CLEANUP_INTERVAL")
+	os.Unsetenv("SESSION_SUMMARIZE")
+	os.Unsetenv("WEBHOOK_WORKERS")
+	os.Unsetenv("WEBHOOK_QUEUE_SIZE")
+	os.Unsetenv("WEBHOOK_DEDUP_DRIVER")
+	os.Unsetenv("WEBHOOK_DEDUP_TTL")
+	os.Unsetenv("RATE_LIMIT_USER_PER_MINUTE")
+	os.Unsetenv("RATE_LIMIT_USER_BURST")
+	os.Unsetenv("RATE_LIMIT_MAX_CONCURRENT")
+	os.Unsetenv("RATE_LIMIT_QUEUE_TIMEOUT")
+	os.Unsetenv("TRANSCRIPTION_TIMEOUT")
 }
 
 // TestSessionStructFieldsUnmarshal tests that Session struct fields are properly unmarshaled from config
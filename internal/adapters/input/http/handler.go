@@ -14,21 +14,41 @@ import (
 
 // HTTPHandler struct - Primary/Driving adapter for HTTP
 type HTTPHandler struct {
-	srv       input.TodoService
-	db        *gorm.DB
-	validator validator.Validator
+	srv             input.TodoService
+	db              *gorm.DB
+	validator       validator.Validator
+	circuitBreakers map[string]CircuitBreakerReporter
+}
+
+// CircuitBreakerReporter interface - LLM client that reports the state of its circuit breaker
+type CircuitBreakerReporter interface {
+	CircuitBreakerStatus() domain.CircuitBreakerStatus
+}
+
+// HTTPHandlerOption func - Configures optional HTTPHandler behavior
+type HTTPHandlerOption func(*HTTPHandler)
+
+// WithCircuitBreakers func - Reports the circuit breakers of the LLM clients, keyed by name, on the health endpoint
+func WithCircuitBreakers(circuitBreakers map[string]CircuitBreakerReporter) HTTPHandlerOption {
+	return func(hdl *HTTPHandler) {
+		hdl.circuitBreakers = circuitBreakers
+	}
 }
 
 // New func - Creates new HTTP handler
-func New(srv input.TodoService, db *gorm.DB) *HTTPHandler {
-	return &HTTPHandler{
+func New(srv input.TodoService, db *gorm.DB, opts ...HTTPHandlerOption) *HTTPHandler {
+	hdl := &HTTPHandler{
 		srv:       srv,
 		db:        db,
 		validator: validator.New(),
 	}
+	for _, opt := range opts {
+		opt(hdl)
+	}
+	return hdl
 }
 
-// HealthCheck func
+// HealthCheck func - Checks the database and reports the LLM circuit breakers
 func (hdl *HTTPHandler) HealthCheck(c *fiber.Ctx) error {
 	sqlDB, err := hdl.db.DB()
 	if err != nil {
@@ -41,7 +61,16 @@ func (hdl *HTTPHandler) HealthCheck(c *fiber.Ctx) error {
 		logrus.Errorln(err)
 		return c.Status(fiber.StatusInternalServerError).JSON(ResponseBody{Status: InternalServerError})
 	}
-	return c.Status(fiber.StatusOK).JSON(ResponseBody{Status: Success, Data: ""})
+
+	// An open circuit is reported but doesn't fail the check; the bot still answers commands
+	if len(hdl.circuitBreakers) == 0 {
+		return c.Status(fiber.StatusOK).JSON(ResponseBody{Status: Success, Data: ""})
+	}
+	llm := make(map[string]domain.CircuitBreakerStatus, len(hdl.circuitBreakers))
+	for name, reporter := range hdl.circuitBreakers {
+		llm[name] = reporter.CircuitBreakerStatus()
+	}
+	return c.Status(fiber.StatusOK).JSON(ResponseBody{Status: Success, Data: HealthResponse{LLM: llm}})
 }
 
 // CreateTodo func
@@ -2,7 +2,13 @@ package http
 
 import (
 	"bytes"
+	"cmp"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
 
 	"golang-template/internal/domain"
 	"golang-template/internal/ports/input"
@@ -81,6 +87,13 @@ func (h *LineWebhookHandler) HandleWebhook(c *fiber.Ctx) error {
 	// Call application service
 	if err := h.service.HandleWebhook(webhookReq); err != nil {
 		logrus.Errorf("Failed to handle webhook: %v", err)
+		// LINE redelivers on non-2xx responses, so signal temporary overload distinctly
+		if errors.Is(err, domain.ErrWebhookQueueFull) || errors.Is(err, domain.ErrWebhookDispatcherClosed) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Webhook queue unavailable",
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Failed to process webhook",
@@ -101,6 +114,12 @@ func (h *LineWebhookHandler) convertToDomainEvent(event webhook.EventInterface)
 		return h.convertFollowEvent(e)
 	case webhook.UnfollowEvent:
 		return h.convertUnfollowEvent(e)
+	case webhook.JoinEvent:
+		return h.convertJoinEvent(e)
+	case webhook.LeaveEvent:
+		return h.convertLeaveEvent(e)
+	case webhook.PostbackEvent:
+		return h.convertPostbackEvent(e)
 	default:
 		logrus.Warnf("Unsupported event type: %T", event)
 		return nil
@@ -110,18 +129,23 @@ func (h *LineWebhookHandler) convertToDomainEvent(event webhook.EventInterface)
 // convertMessageEvent - Converts message event
 func (h *LineWebhookHandler) convertMessageEvent(event webhook.MessageEvent) *domain.LineWebhookEvent {
 	domainEvent := &domain.LineWebhookEvent{
-		Type:       domain.LineEventTypeMessage,
-		ReplyToken: event.ReplyToken,
-		Source:     h.convertSource(event.Source),
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypeMessage,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		ReplyToken:   event.ReplyToken,
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
 	}
 
 	// Convert message based on type
 	switch msg := event.Message.(type) {
 	case webhook.TextMessageContent:
+		text, mentionsBot := stripBotMentions(msg.Text, msg.Mention)
 		domainEvent.Message = &domain.LineMessage{
-			ID:   msg.Id,
-			Type: domain.LineMessageTypeText,
-			Text: msg.Text,
+			ID:          msg.Id,
+			Type:        domain.LineMessageTypeText,
+			Text:        text,
+			MentionsBot: mentionsBot,
 		}
 	case webhook.StickerMessageContent:
 		domainEvent.Message = &domain.LineMessage{
@@ -129,12 +153,28 @@ func (h *LineWebhookHandler) convertMessageEvent(event webhook.MessageEvent) *do
 			Type:      domain.LineMessageTypeSticker,
 			PackageID: msg.PackageId,
 			StickerID: msg.StickerId,
+			Keywords:  msg.Keywords,
 		}
 	case webhook.ImageMessageContent:
 		domainEvent.Message = &domain.LineMessage{
 			ID:   msg.Id,
 			Type: domain.LineMessageTypeImage,
 		}
+	case webhook.LocationMessageContent:
+		domainEvent.Message = &domain.LineMessage{
+			ID:        msg.Id,
+			Type:      domain.LineMessageTypeLocation,
+			Title:     msg.Title,
+			Address:   msg.Address,
+			Latitude:  msg.Latitude,
+			Longitude: msg.Longitude,
+		}
+	case webhook.AudioMessageContent:
+		domainEvent.Message = &domain.LineMessage{
+			ID:       msg.Id,
+			Type:     domain.LineMessageTypeAudio,
+			Duration: msg.Duration,
+		}
 	default:
 		logrus.Warnf("Unsupported message type: %T", msg)
 		return nil
@@ -146,20 +186,112 @@ func (h *LineWebhookHandler) convertMessageEvent(event webhook.MessageEvent) *do
 // convertFollowEvent - Converts follow event
 func (h *LineWebhookHandler) convertFollowEvent(event webhook.FollowEvent) *domain.LineWebhookEvent {
 	return &domain.LineWebhookEvent{
-		Type:       domain.LineEventTypeFollow,
-		ReplyToken: event.ReplyToken,
-		Source:     h.convertSource(event.Source),
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypeFollow,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		ReplyToken:   event.ReplyToken,
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
 	}
 }
 
 // convertUnfollowEvent - Converts unfollow event
 func (h *LineWebhookHandler) convertUnfollowEvent(event webhook.UnfollowEvent) *domain.LineWebhookEvent {
 	return &domain.LineWebhookEvent{
-		Type:   domain.LineEventTypeUnfollow,
-		Source: h.convertSource(event.Source),
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypeUnfollow,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
+	}
+}
+
+// convertJoinEvent - Converts join event (bot added to a group or room)
+func (h *LineWebhookHandler) convertJoinEvent(event webhook.JoinEvent) *domain.LineWebhookEvent {
+	return &domain.LineWebhookEvent{
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypeJoin,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		ReplyToken:   event.ReplyToken,
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
+	}
+}
+
+// convertLeaveEvent - Converts leave event (bot removed from a group or room)
+func (h *LineWebhookHandler) convertLeaveEvent(event webhook.LeaveEvent) *domain.LineWebhookEvent {
+	return &domain.LineWebhookEvent{
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypeLeave,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
 	}
 }
 
+// convertPostbackEvent - Converts postback event
+func (h *LineWebhookHandler) convertPostbackEvent(event webhook.PostbackEvent) *domain.LineWebhookEvent {
+	domainEvent := &domain.LineWebhookEvent{
+		ID:           event.WebhookEventId,
+		Type:         domain.LineEventTypePostback,
+		Timestamp:    time.UnixMilli(event.Timestamp),
+		ReplyToken:   event.ReplyToken,
+		Source:       h.convertSource(event.Source),
+		IsRedelivery: isRedelivery(event.DeliveryContext),
+	}
+
+	if event.Postback != nil {
+		domainEvent.Postback = &domain.LinePostback{
+			Data:   event.Postback.Data,
+			Params: event.Postback.Params,
+		}
+	}
+
+	return domainEvent
+}
+
+// stripBotMentions - Removes @mentions of the bot from text and reports whether there were any
+// LINE reports mention positions in UTF-16 code units. Mentions whose position lies outside
+// the text, or overlaps one already removed, are left in place.
+func stripBotMentions(text string, mention *webhook.Mention) (string, bool) {
+	if mention == nil {
+		return text, false
+	}
+
+	var selfMentions []webhook.UserMentionee
+	for _, m := range mention.Mentionees {
+		if mentionee, ok := m.(webhook.UserMentionee); ok && mentionee.IsSelf {
+			selfMentions = append(selfMentions, mentionee)
+		}
+	}
+	if len(selfMentions) == 0 {
+		return text, false
+	}
+
+	// Remove from the end so earlier indexes stay valid; LINE doesn't guarantee the order
+	slices.SortFunc(selfMentions, func(a, b webhook.UserMentionee) int {
+		return cmp.Compare(b.Index, a.Index)
+	})
+
+	units := utf16.Encode([]rune(text))
+	limit := len(units)
+	for _, mentionee := range selfMentions {
+		start, end := int(mentionee.Index), int(mentionee.Index)+int(mentionee.Length)
+		if start < 0 || start > end || end > limit {
+			continue
+		}
+		units = append(units[:start], units[end:]...)
+		limit = start
+	}
+
+	return strings.TrimSpace(string(utf16.Decode(units))), true
+}
+
+// isRedelivery - Reports whether LINE flagged the event as a redelivery
+func isRedelivery(deliveryContext *webhook.DeliveryContext) bool {
+	return deliveryContext != nil && deliveryContext.IsRedelivery
+}
+
 // convertSource - Converts event source
 func (h *LineWebhookHandler) convertSource(source webhook.SourceInterface) domain.LineSource {
 	switch s := source.(type) {
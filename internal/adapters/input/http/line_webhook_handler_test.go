@@ -0,0 +1,114 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/webhook"
+)
+
+// TestStripBotMentions tests that only the bot's own mentions are removed, using UTF-16 positions
+func TestStripBotMentions(t *testing.T) {
+	self := func(index, length int32) webhook.UserMentionee {
+		return webhook.UserMentionee{Index: index, Length: length, IsSelf: true}
+	}
+	other := func(index, length int32) webhook.UserMentionee {
+		return webhook.UserMentionee{Index: index, Length: length, UserId: "U-other"}
+	}
+
+	tests := []struct {
+		name        string
+		text        string
+		mentionees  []webhook.MentioneeInterface
+		expected    string
+		mentionsBot bool
+	}{
+		{
+			name:        "no mention",
+			text:        "hello",
+			expected:    "hello",
+			mentionsBot: false,
+		},
+		{
+			name:        "single mention at start",
+			text:        "@Bot hello",
+			mentionees:  []webhook.MentioneeInterface{self(0, 4)},
+			expected:    "hello",
+			mentionsBot: true,
+		},
+		{
+			// 😀 is two UTF-16 code units
+			name:        "emoji before mention",
+			text:        "😀 @Bot hello",
+			mentionees:  []webhook.MentioneeInterface{self(3, 4)},
+			expected:    "😀  hello",
+			mentionsBot: true,
+		},
+		{
+			name:        "thai text before mention",
+			text:        "สวัสดี @Bot ช่วยหน่อย",
+			mentionees:  []webhook.MentioneeInterface{self(7, 4)},
+			expected:    "สวัสดี  ช่วยหน่อย",
+			mentionsBot: true,
+		},
+		{
+			name:        "several mentions",
+			text:        "@Bot hi @Bot",
+			mentionees:  []webhook.MentioneeInterface{self(0, 4), self(8, 4)},
+			expected:    "hi",
+			mentionsBot: true,
+		},
+		{
+			name:        "out of order mentionees",
+			text:        "@Bot hi @Bot there",
+			mentionees:  []webhook.MentioneeInterface{self(8, 4), self(0, 4)},
+			expected:    "hi  there",
+			mentionsBot: true,
+		},
+		{
+			name:        "out of range index",
+			text:        "@Bot hi",
+			mentionees:  []webhook.MentioneeInterface{self(0, 4), self(5, 10), self(-1, 2)},
+			expected:    "hi",
+			mentionsBot: true,
+		},
+		{
+			name:        "overlapping mentions",
+			text:        "@Bot hi",
+			mentionees:  []webhook.MentioneeInterface{self(0, 4), self(2, 4)},
+			expected:    "@Bi",
+			mentionsBot: true,
+		},
+		{
+			name:        "bot mixed with other users",
+			text:        "@Alice @Bot hi @Carol",
+			mentionees:  []webhook.MentioneeInterface{other(0, 6), self(7, 4), other(15, 6)},
+			expected:    "@Alice  hi @Carol",
+			mentionsBot: true,
+		},
+		{
+			name:        "only other users and everyone",
+			text:        "@Alice @All hi",
+			mentionees:  []webhook.MentioneeInterface{other(0, 6), webhook.AllMentionee{Index: 7, Length: 4}},
+			expected:    "@Alice @All hi",
+			mentionsBot: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var mention *webhook.Mention
+			if tt.mentionees != nil {
+				mention = &webhook.Mention{Mentionees: tt.mentionees}
+			}
+
+			// Act
+			text, mentionsBot := stripBotMentions(tt.text, mention)
+
+			// Assert
+			if text != tt.expected || mentionsBot != tt.mentionsBot {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.mentionsBot, text, mentionsBot)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"time"
 
+	"golang-template/internal/domain"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -42,6 +44,11 @@ type Status struct {
 }
 
 type (
+	// HealthResponse struct - HTTP response DTO for the health check
+	HealthResponse struct {
+		LLM map[string]domain.CircuitBreakerStatus `json:"llm,omitempty"`
+	}
+
 	// TodoResponse struct - HTTP response DTO for a single todo
 	TodoResponse struct {
 		ID          *uuid.UUID      `json:"id,omitempty" mapstructure:"id"`
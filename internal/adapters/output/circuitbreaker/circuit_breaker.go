@@ -0,0 +1,129 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default circuit breaker configuration
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+// CircuitBreaker struct - Stops calls to a failing LLM server so requests fail fast
+// The circuit opens after a number of consecutive failures. Once the cooldown has passed it
+// half-opens and lets a single probe through: a successful probe closes the circuit, a failed
+// one opens it for another cooldown. Calls are refused while open and while a probe is running.
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu                  sync.Mutex
+	state               domain.CircuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probeStartedAt      time.Time // Zero when no probe is running
+}
+
+// NewCircuitBreaker func - Creates a closed circuit breaker
+// A threshold or cooldown of zero or less uses the default. name is only used in logs and errors.
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+		state:            domain.CircuitStateClosed,
+	}
+}
+
+// Allow reports whether a call may be made, returning an error wrapping domain.ErrLMStudioUnavailable if not
+// Every allowed call must be followed by RecordSuccess or RecordFailure. A probe that is never
+// recorded, for example because its request was cancelled, is replaced after another cooldown.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case domain.CircuitStateOpen:
+		if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return fmt.Errorf("%w: %s circuit breaker open, retry in %v", domain.ErrLMStudioUnavailable, b.name, wait.Round(time.Second))
+		}
+		b.state = domain.CircuitStateHalfOpen
+		b.probeStartedAt = now
+		logrus.Infof("%s circuit breaker half-open, sending probe", b.name)
+		return nil
+	case domain.CircuitStateHalfOpen:
+		if !b.probeStartedAt.IsZero() && now.Sub(b.probeStartedAt) < b.cooldown {
+			return fmt.Errorf("%w: %s circuit breaker half-open, waiting for probe", domain.ErrLMStudioUnavailable, b.name)
+		}
+		b.probeStartedAt = now
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess records a call that reached the server, closing the circuit
+// Client errors such as 4xx responses count as successes since the server answered.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != domain.CircuitStateClosed {
+		logrus.Infof("%s circuit breaker closed", b.name)
+	}
+	b.state = domain.CircuitStateClosed
+	b.consecutiveFailures = 0
+	b.probeStartedAt = time.Time{}
+}
+
+// RecordFailure records a call that failed because the server was unavailable
+// The circuit opens once the failures reach the threshold, or at once when a probe fails.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	if b.state == domain.CircuitStateHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		if b.state != domain.CircuitStateOpen {
+			logrus.Warnf("%s circuit breaker open after %d consecutive failures, failing fast for %v", b.name, b.consecutiveFailures, b.cooldown)
+		}
+		b.state = domain.CircuitStateOpen
+		b.openedAt = b.now()
+		b.probeStartedAt = time.Time{}
+	}
+}
+
+// Status returns the current state of the circuit
+// An open circuit whose cooldown has passed is still reported open until the next call probes it.
+func (b *CircuitBreaker) Status() domain.CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := domain.CircuitBreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+	}
+	if b.state != domain.CircuitStateClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
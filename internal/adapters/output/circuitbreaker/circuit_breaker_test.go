@@ -0,0 +1,107 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang-template/internal/domain"
+)
+
+// newTestBreaker creates a breaker with a controllable clock
+func newTestBreaker(threshold int, cooldown time.Duration) (*CircuitBreaker, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker("lmstudio", threshold, cooldown)
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+// TestNewCircuitBreakerDefaults tests that non-positive settings use the defaults
+func TestNewCircuitBreakerDefaults(t *testing.T) {
+	breaker := NewCircuitBreaker("lmstudio", 0, -time.Second)
+
+	if breaker.failureThreshold != DefaultFailureThreshold || breaker.cooldown != DefaultCooldown {
+		t.Errorf("expected defaults, got threshold=%d cooldown=%v", breaker.failureThreshold, breaker.cooldown)
+	}
+	if breaker.Status().State != domain.CircuitStateClosed {
+		t.Errorf("expected a new breaker to be closed, got %s", breaker.Status().State)
+	}
+}
+
+// TestCircuitBreakerOpensAfterConsecutiveFailures tests that the circuit opens at the threshold and fails fast
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	// Arrange
+	breaker, _ := newTestBreaker(3, time.Minute)
+
+	// Act
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+	breaker.RecordSuccess() // Resets the count
+	breaker.RecordFailure()
+	breaker.RecordFailure()
+	stillClosed := breaker.Allow()
+	breaker.RecordFailure()
+	err := breaker.Allow()
+
+	// Assert
+	if stillClosed != nil {
+		t.Errorf("expected calls allowed below the threshold, got %v", stillClosed)
+	}
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable while open, got %v", err)
+	}
+	status := breaker.Status()
+	if status.State != domain.CircuitStateOpen || status.ConsecutiveFailures != 3 || status.OpenedAt == nil {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+// TestCircuitBreakerHalfOpenProbe tests that one probe is let through after the cooldown and decides the state
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	// Arrange
+	breaker, now := newTestBreaker(1, time.Minute)
+	breaker.RecordFailure()
+	*now = now.Add(time.Minute)
+
+	// Act
+	probe := breaker.Allow()
+	concurrent := breaker.Allow()
+	breaker.RecordFailure()
+	reopened := breaker.Status().State
+	*now = now.Add(time.Minute)
+	secondProbe := breaker.Allow()
+	breaker.RecordSuccess()
+
+	// Assert
+	if probe != nil {
+		t.Errorf("expected a probe after the cooldown, got %v", probe)
+	}
+	if !errors.Is(concurrent, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected other calls refused while probing, got %v", concurrent)
+	}
+	if reopened != domain.CircuitStateOpen {
+		t.Errorf("expected a failed probe to reopen the circuit, got %s", reopened)
+	}
+	if secondProbe != nil {
+		t.Errorf("expected another probe after the next cooldown, got %v", secondProbe)
+	}
+	if status := breaker.Status(); status.State != domain.CircuitStateClosed || status.ConsecutiveFailures != 0 || status.OpenedAt != nil {
+		t.Errorf("expected a successful probe to close the circuit, got %+v", status)
+	}
+}
+
+// TestCircuitBreakerReplacesLostProbe tests that a probe that is never recorded doesn't keep the circuit half-open
+func TestCircuitBreakerReplacesLostProbe(t *testing.T) {
+	breaker, now := newTestBreaker(1, time.Minute)
+	breaker.RecordFailure()
+	*now = now.Add(time.Minute)
+
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("expected a probe, got %v", err)
+	}
+	*now = now.Add(time.Minute)
+
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("expected a new probe once the lost one timed out, got %v", err)
+	}
+}
@@ -2,16 +2,26 @@ package line
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 
 	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
 
 	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
 	"github.com/sirupsen/logrus"
 )
 
+// Compile-time check to ensure LineClientAdapter implements LineClient interface
+var _ output.LineClient = (*LineClientAdapter)(nil)
+
+// Largest message content downloaded from LINE (10 MB, LINE's limit for images)
+const maxMessageContentBytes = 10 << 20
+
 // LineClientAdapter struct - Output adapter for LINE messaging platform
 type LineClientAdapter struct {
-	client *messaging_api.MessagingApiAPI
+	client     *messaging_api.MessagingApiAPI
+	blobClient *messaging_api.MessagingApiBlobAPI
 }
 
 // NewLineClientAdapter func - Creates new LINE client adapter
@@ -21,8 +31,14 @@ func NewLineClientAdapter(channelToken string) (*LineClientAdapter, error) {
 		return nil, fmt.Errorf("failed to create LINE messaging API client: %w", err)
 	}
 
+	blobClient, err := messaging_api.NewMessagingApiBlobAPI(channelToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LINE messaging API blob client: %w", err)
+	}
+
 	return &LineClientAdapter{
-		client: client,
+		client:     client,
+		blobClient: blobClient,
 	}, nil
 }
 
@@ -100,6 +116,76 @@ func (a *LineClientAdapter) PushMessage(request domain.LinePushMessageRequest) (
 	}, nil
 }
 
+// BroadcastMessage - Sends messages to every user who has added the bot as a friend
+func (a *LineClientAdapter) BroadcastMessage(request domain.LineBroadcastRequest) (*domain.LineMessageResponse, error) {
+	// Convert domain messages to LINE SDK messages
+	messages := make([]messaging_api.MessageInterface, 0, len(request.Messages))
+
+	for _, msg := range request.Messages {
+		lineMsg, err := a.convertToLineMessage(msg)
+		if err != nil {
+			logrus.Errorf("Failed to convert message: %v", err)
+			continue
+		}
+		messages = append(messages, lineMsg)
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no valid messages to send")
+	}
+
+	// Send broadcast via LINE SDK
+	req := &messaging_api.BroadcastRequest{
+		Messages: messages,
+	}
+
+	_, err := a.client.Broadcast(req, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send broadcast message: %w", err)
+	}
+
+	logrus.Infof("Successfully sent broadcast message")
+
+	return &domain.LineMessageResponse{
+		Status:  "success",
+		Message: "Broadcast message sent successfully",
+	}, nil
+}
+
+// GetMessageContent - Downloads the content of an image, video, audio, or file message
+// Content larger than maxMessageContentBytes is rejected with domain.ErrLineContentTooLarge.
+func (a *LineClientAdapter) GetMessageContent(messageID string) (*domain.LineMessageContent, error) {
+	resp, err := a.blobClient.GetMessageContent(messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.ContentLength > maxMessageContentBytes {
+		return nil, fmt.Errorf("%w: %d bytes", domain.ErrLineContentTooLarge, resp.ContentLength)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageContentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message content: %w", err)
+	}
+	if len(data) > maxMessageContentBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", domain.ErrLineContentTooLarge, maxMessageContentBytes)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	logrus.Infof("Downloaded message content: messageID=%s, type=%s, size=%d", messageID, contentType, len(data))
+
+	return &domain.LineMessageContent{
+		Data:        data,
+		ContentType: contentType,
+	}, nil
+}
+
 // GetProfile - Gets user profile information
 func (a *LineClientAdapter) GetProfile(userID string) (interface{}, error) {
 	profile, err := a.client.GetProfile(userID)
@@ -112,19 +198,205 @@ func (a *LineClientAdapter) GetProfile(userID string) (interface{}, error) {
 
 // convertToLineMessage - Helper function to convert domain message to LINE SDK message
 func (a *LineClientAdapter) convertToLineMessage(msg domain.LineOutgoingMessage) (messaging_api.MessageInterface, error) {
+	quickReply, err := a.convertQuickReply(msg.QuickReply)
+	if err != nil {
+		return nil, err
+	}
+
 	switch msg.Type {
 	case domain.LineMessageTypeText:
 		return &messaging_api.TextMessage{
-			Text: msg.Text,
+			Text:       msg.Text,
+			QuickReply: quickReply,
 		}, nil
 
 	case domain.LineMessageTypeSticker:
 		return &messaging_api.StickerMessage{
-			PackageId: msg.PackageID,
-			StickerId: msg.StickerID,
+			PackageId:  msg.PackageID,
+			StickerId:  msg.StickerID,
+			QuickReply: quickReply,
+		}, nil
+
+	case domain.LineMessageTypeImage:
+		return &messaging_api.ImageMessage{
+			OriginalContentUrl: msg.OriginalContentURL,
+			PreviewImageUrl:    msg.PreviewImageURL,
+			QuickReply:         quickReply,
+		}, nil
+
+	case domain.LineMessageTypeVideo:
+		return &messaging_api.VideoMessage{
+			OriginalContentUrl: msg.OriginalContentURL,
+			PreviewImageUrl:    msg.PreviewImageURL,
+			QuickReply:         quickReply,
+		}, nil
+
+	case domain.LineMessageTypeAudio:
+		return &messaging_api.AudioMessage{
+			OriginalContentUrl: msg.OriginalContentURL,
+			Duration:           msg.Duration,
+			QuickReply:         quickReply,
+		}, nil
+
+	case domain.LineMessageTypeLocation:
+		return &messaging_api.LocationMessage{
+			Title:      msg.Title,
+			Address:    msg.Address,
+			Latitude:   msg.Latitude,
+			Longitude:  msg.Longitude,
+			QuickReply: quickReply,
+		}, nil
+
+	case domain.LineMessageTypeTemplate:
+		template, err := a.convertTemplate(msg.Template)
+		if err != nil {
+			return nil, err
+		}
+		return &messaging_api.TemplateMessage{
+			AltText:    msg.AltText,
+			Template:   template,
+			QuickReply: quickReply,
+		}, nil
+
+	case domain.LineMessageTypeFlex:
+		contents, err := messaging_api.UnmarshalFlexContainer(msg.FlexContents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid flex contents: %w", err)
+		}
+		return &messaging_api.FlexMessage{
+			AltText:    msg.AltText,
+			Contents:   contents,
+			QuickReply: quickReply,
 		}, nil
 
 	default:
 		return nil, fmt.Errorf("unsupported message type: %s", msg.Type)
 	}
 }
+
+// convertTemplate - Helper function to convert domain template to LINE SDK template
+func (a *LineClientAdapter) convertTemplate(template *domain.LineTemplate) (messaging_api.TemplateInterface, error) {
+	if template == nil {
+		return nil, fmt.Errorf("template message has no template")
+	}
+
+	actions, err := a.convertActions(template.Actions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch template.Type {
+	case domain.LineTemplateTypeButtons:
+		return &messaging_api.ButtonsTemplate{
+			ThumbnailImageUrl: template.ThumbnailImageURL,
+			Title:             template.Title,
+			Text:              template.Text,
+			Actions:           actions,
+		}, nil
+
+	case domain.LineTemplateTypeConfirm:
+		return &messaging_api.ConfirmTemplate{
+			Text:    template.Text,
+			Actions: actions,
+		}, nil
+
+	case domain.LineTemplateTypeCarousel:
+		columns := make([]messaging_api.CarouselColumn, 0, len(template.Columns))
+		for _, column := range template.Columns {
+			columnActions, err := a.convertActions(column.Actions)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, messaging_api.CarouselColumn{
+				ThumbnailImageUrl: column.ThumbnailImageURL,
+				Title:             column.Title,
+				Text:              column.Text,
+				Actions:           columnActions,
+			})
+		}
+		return &messaging_api.CarouselTemplate{
+			Columns: columns,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported template type: %s", template.Type)
+	}
+}
+
+// convertQuickReply - Helper function to convert domain quick reply items to LINE SDK quick reply
+// Returns nil when there are no items so the message is sent without quick reply buttons.
+func (a *LineClientAdapter) convertQuickReply(items []domain.LineQuickReplyItem) (*messaging_api.QuickReply, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	quickReplyItems := make([]messaging_api.QuickReplyItem, 0, len(items))
+	for _, item := range items {
+		action, err := a.convertAction(item.Action)
+		if err != nil {
+			return nil, err
+		}
+		quickReplyItems = append(quickReplyItems, messaging_api.QuickReplyItem{
+			Type:     "action",
+			ImageUrl: item.ImageURL,
+			Action:   action,
+		})
+	}
+
+	return &messaging_api.QuickReply{Items: quickReplyItems}, nil
+}
+
+// convertActions - Helper function to convert domain actions to LINE SDK actions
+func (a *LineClientAdapter) convertActions(actions []domain.LineAction) ([]messaging_api.ActionInterface, error) {
+	lineActions := make([]messaging_api.ActionInterface, 0, len(actions))
+	for _, action := range actions {
+		lineAction, err := a.convertAction(action)
+		if err != nil {
+			return nil, err
+		}
+		lineActions = append(lineActions, lineAction)
+	}
+	return lineActions, nil
+}
+
+// convertAction - Helper function to convert domain action to LINE SDK action
+func (a *LineClientAdapter) convertAction(action domain.LineAction) (messaging_api.ActionInterface, error) {
+	switch action.Type {
+	case domain.LineActionTypeMessage:
+		return &messaging_api.MessageAction{
+			Label: action.Label,
+			Text:  action.Text,
+		}, nil
+
+	case domain.LineActionTypePostback:
+		return &messaging_api.PostbackAction{
+			Label:       action.Label,
+			Data:        action.Data,
+			DisplayText: action.Text,
+		}, nil
+
+	case domain.LineActionTypeURI:
+		return &messaging_api.UriAction{
+			Label: action.Label,
+			Uri:   action.URI,
+		}, nil
+
+	case domain.LineActionTypeLocation:
+		return &messaging_api.LocationAction{
+			Label: action.Label,
+		}, nil
+
+	case domain.LineActionTypeCamera:
+		return &messaging_api.CameraAction{
+			Label: action.Label,
+		}, nil
+
+	case domain.LineActionTypeCameraRoll:
+		return &messaging_api.CameraRollAction{
+			Label: action.Label,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported action type: %s", action.Type)
+	}
+}
@@ -0,0 +1,263 @@
+package line
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+
+	"github.com/line/line-bot-sdk-go/v8/linebot/messaging_api"
+)
+
+// marshalMessage converts a domain message and returns the JSON sent to LINE
+func marshalMessage(t *testing.T, msg domain.LineOutgoingMessage) map[string]interface{} {
+	t.Helper()
+
+	lineMsg, err := (&LineClientAdapter{}).convertToLineMessage(msg)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	data, err := json.Marshal(lineMsg)
+	if err != nil {
+		t.Fatalf("failed to marshal message: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal message: %v", err)
+	}
+	return decoded
+}
+
+// TestConvertToLineMessageMediaAndLocation tests that media and location messages map onto the SDK types
+func TestConvertToLineMessageMediaAndLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      domain.LineOutgoingMessage
+		expected messaging_api.MessageInterface
+	}{
+		{
+			name: "image",
+			msg: domain.LineOutgoingMessage{
+				Type:               domain.LineMessageTypeImage,
+				OriginalContentURL: "https://example.com/a.jpg",
+				PreviewImageURL:    "https://example.com/a-preview.jpg",
+			},
+			expected: &messaging_api.ImageMessage{
+				OriginalContentUrl: "https://example.com/a.jpg",
+				PreviewImageUrl:    "https://example.com/a-preview.jpg",
+			},
+		},
+		{
+			name: "video",
+			msg: domain.LineOutgoingMessage{
+				Type:               domain.LineMessageTypeVideo,
+				OriginalContentURL: "https://example.com/a.mp4",
+				PreviewImageURL:    "https://example.com/a.jpg",
+			},
+			expected: &messaging_api.VideoMessage{
+				OriginalContentUrl: "https://example.com/a.mp4",
+				PreviewImageUrl:    "https://example.com/a.jpg",
+			},
+		},
+		{
+			name: "audio",
+			msg: domain.LineOutgoingMessage{
+				Type:               domain.LineMessageTypeAudio,
+				OriginalContentURL: "https://example.com/a.m4a",
+				Duration:           60000,
+			},
+			expected: &messaging_api.AudioMessage{
+				OriginalContentUrl: "https://example.com/a.m4a",
+				Duration:           60000,
+			},
+		},
+		{
+			name: "location",
+			msg: domain.LineOutgoingMessage{
+				Type:      domain.LineMessageTypeLocation,
+				Title:     "Office",
+				Address:   "Bangkok",
+				Latitude:  13.7563,
+				Longitude: 100.5018,
+			},
+			expected: &messaging_api.LocationMessage{
+				Title:     "Office",
+				Address:   "Bangkok",
+				Latitude:  13.7563,
+				Longitude: 100.5018,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&LineClientAdapter{}).convertToLineMessage(tt.msg)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			gotJSON, _ := json.Marshal(got)
+			expectedJSON, _ := json.Marshal(tt.expected)
+			if string(gotJSON) != string(expectedJSON) {
+				t.Errorf("expected %s, got %s", expectedJSON, gotJSON)
+			}
+			if !strings.Contains(string(gotJSON), `"type":"`+tt.name+`"`) {
+				t.Errorf("expected type %q in %s", tt.name, gotJSON)
+			}
+		})
+	}
+}
+
+// TestConvertToLineMessageTemplates tests that buttons, confirm, and carousel templates map onto the SDK types
+func TestConvertToLineMessageTemplates(t *testing.T) {
+	yes := domain.LineAction{Type: domain.LineActionTypePostback, Label: "Yes", Data: "action=confirm", Text: "Yes"}
+	no := domain.LineAction{Type: domain.LineActionTypeMessage, Label: "No", Text: "No"}
+	open := domain.LineAction{Type: domain.LineActionTypeURI, Label: "Open", URI: "https://example.com"}
+
+	t.Run("buttons", func(t *testing.T) {
+		decoded := marshalMessage(t, domain.LineOutgoingMessage{
+			Type:    domain.LineMessageTypeTemplate,
+			AltText: "Menu",
+			Template: &domain.LineTemplate{
+				Type:    domain.LineTemplateTypeButtons,
+				Title:   "Menu",
+				Text:    "Pick one",
+				Actions: []domain.LineAction{yes, open},
+			},
+		})
+
+		template := decoded["template"].(map[string]interface{})
+		if decoded["type"] != "template" || decoded["altText"] != "Menu" || template["type"] != "buttons" {
+			t.Fatalf("expected buttons template message, got %v", decoded)
+		}
+		actions := template["actions"].([]interface{})
+		postback := actions[0].(map[string]interface{})
+		if postback["type"] != "postback" || postback["data"] != "action=confirm" || postback["displayText"] != "Yes" {
+			t.Errorf("expected postback action, got %v", postback)
+		}
+		if uri := actions[1].(map[string]interface{}); uri["type"] != "uri" || uri["uri"] != "https://example.com" {
+			t.Errorf("expected uri action, got %v", uri)
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		decoded := marshalMessage(t, domain.LineOutgoingMessage{
+			Type:    domain.LineMessageTypeTemplate,
+			AltText: "Are you sure?",
+			Template: &domain.LineTemplate{
+				Type:    domain.LineTemplateTypeConfirm,
+				Text:    "Are you sure?",
+				Actions: []domain.LineAction{yes, no},
+			},
+		})
+
+		template := decoded["template"].(map[string]interface{})
+		if template["type"] != "confirm" || len(template["actions"].([]interface{})) != 2 {
+			t.Errorf("expected confirm template with 2 actions, got %v", template)
+		}
+	})
+
+	t.Run("carousel", func(t *testing.T) {
+		decoded := marshalMessage(t, domain.LineOutgoingMessage{
+			Type:    domain.LineMessageTypeTemplate,
+			AltText: "Items",
+			Template: &domain.LineTemplate{
+				Type: domain.LineTemplateTypeCarousel,
+				Columns: []domain.LineTemplateColumn{
+					{Title: "First", Text: "One", Actions: []domain.LineAction{open}},
+					{Title: "Second", Text: "Two", Actions: []domain.LineAction{no}},
+				},
+			},
+		})
+
+		template := decoded["template"].(map[string]interface{})
+		columns := template["columns"].([]interface{})
+		if template["type"] != "carousel" || len(columns) != 2 {
+			t.Fatalf("expected carousel template with 2 columns, got %v", template)
+		}
+		if column := columns[1].(map[string]interface{}); column["title"] != "Second" || len(column["actions"].([]interface{})) != 1 {
+			t.Errorf("expected second column with 1 action, got %v", column)
+		}
+	})
+}
+
+// TestConvertToLineMessageFlex tests that flex contents are sent as the message's container
+func TestConvertToLineMessageFlex(t *testing.T) {
+	decoded := marshalMessage(t, domain.LineOutgoingMessage{
+		Type:         domain.LineMessageTypeFlex,
+		AltText:      "Receipt",
+		FlexContents: json.RawMessage(`{"type":"bubble","body":{"type":"box","layout":"vertical","contents":[{"type":"text","text":"Total"}]}}`),
+	})
+
+	if decoded["type"] != "flex" || decoded["altText"] != "Receipt" {
+		t.Fatalf("expected flex message, got %v", decoded)
+	}
+	contents := decoded["contents"].(map[string]interface{})
+	body := contents["body"].(map[string]interface{})
+	text := body["contents"].([]interface{})[0].(map[string]interface{})
+	if contents["type"] != "bubble" || text["text"] != "Total" {
+		t.Errorf("expected bubble with text, got %v", contents)
+	}
+}
+
+// TestConvertToLineMessageQuickReply tests that quick reply items are attached to the message
+func TestConvertToLineMessageQuickReply(t *testing.T) {
+	decoded := marshalMessage(t, domain.LineOutgoingMessage{
+		Type: domain.LineMessageTypeText,
+		Text: "Where are you?",
+		QuickReply: []domain.LineQuickReplyItem{
+			{Action: domain.LineAction{Type: domain.LineActionTypeLocation, Label: "Send location"}},
+			{ImageURL: "https://example.com/icon.png", Action: domain.LineAction{Type: domain.LineActionTypeMessage, Label: "Home", Text: "I'm home"}},
+		},
+	})
+
+	items := decoded["quickReply"].(map[string]interface{})["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("expected 2 quick reply items, got %v", items)
+	}
+	first := items[0].(map[string]interface{})
+	if first["type"] != "action" || first["action"].(map[string]interface{})["type"] != "location" {
+		t.Errorf("expected location quick reply item, got %v", first)
+	}
+	second := items[1].(map[string]interface{})
+	if second["imageUrl"] != "https://example.com/icon.png" || second["action"].(map[string]interface{})["text"] != "I'm home" {
+		t.Errorf("expected message quick reply item with icon, got %v", second)
+	}
+
+	// Messages without quick reply items are sent without the field
+	if plain := marshalMessage(t, domain.LineOutgoingMessage{Type: domain.LineMessageTypeText, Text: "Hi"}); plain["quickReply"] != nil {
+		t.Errorf("expected no quickReply, got %v", plain["quickReply"])
+	}
+}
+
+// TestConvertToLineMessageErrors tests that invalid messages are rejected
+func TestConvertToLineMessageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  domain.LineOutgoingMessage
+	}{
+		{"unsupported message type", domain.LineOutgoingMessage{Type: domain.LineMessageTypeFile}},
+		{"template without template", domain.LineOutgoingMessage{Type: domain.LineMessageTypeTemplate, AltText: "Menu"}},
+		{"unsupported template type", domain.LineOutgoingMessage{
+			Type:     domain.LineMessageTypeTemplate,
+			Template: &domain.LineTemplate{Type: "image_carousel"},
+		}},
+		{"invalid flex contents", domain.LineOutgoingMessage{Type: domain.LineMessageTypeFlex, FlexContents: json.RawMessage(`not json`)}},
+		{"unsupported action type", domain.LineOutgoingMessage{
+			Type:       domain.LineMessageTypeText,
+			Text:       "Hi",
+			QuickReply: []domain.LineQuickReplyItem{{Action: domain.LineAction{Type: "datetimepicker"}}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&LineClientAdapter{}).convertToLineMessage(tt.msg); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
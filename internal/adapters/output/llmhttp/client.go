@@ -0,0 +1,270 @@
+package llmhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-template/internal/adapters/output/circuitbreaker"
+	"golang-template/internal/adapters/output/retry"
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Client struct - Sends requests to an LLM server, retrying transient failures behind a circuit breaker
+// The LM Studio and Ollama adapters build the HTTP requests; Client decides whether they are
+// sent, retried, and how failed responses become errors.
+type Client struct {
+	name         string
+	policy       retry.RetryPolicy
+	breaker      *circuitbreaker.CircuitBreaker
+	errorMessage func(body []byte) string
+}
+
+// ClientOption func - Functional option for configuring Client
+type ClientOption func(*Client)
+
+// WithErrorMessage func - Extracts the error message from the body of a failed response
+// By default the whole body is used.
+func WithErrorMessage(errorMessage func(body []byte) string) ClientOption {
+	return func(c *Client) {
+		if errorMessage != nil {
+			c.errorMessage = errorMessage
+		}
+	}
+}
+
+// NewClient func - Creates a client for the server called name in logs, e.g. "LM Studio"
+func NewClient(name string, policy retry.RetryPolicy, breaker *circuitbreaker.CircuitBreaker, opts ...ClientOption) *Client {
+	c := &Client{
+		name:         name,
+		policy:       policy,
+		breaker:      breaker,
+		errorMessage: func(body []byte) string { return string(body) },
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Do executes an operation, retrying transient failures as the retry policy allows
+// Network errors, 5xx and 429 responses are retried; other 4xx responses fail at once with
+// domain.ErrInvalidRequest. Retrying stops when the attempts or the retry budget run out, or the
+// circuit breaker opens. The operation must send its request with the context it is given, which
+// ends with the retry budget; the returned response body releases it when closed.
+func (c *Client) Do(ctx context.Context, operation func(ctx context.Context) (*http.Response, error)) (*http.Response, error) {
+	start := time.Now()
+
+	// Bound the attempts themselves by the budget, not only the waits between them
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if c.policy.Budget > 0 {
+		attemptCtx, cancel = context.WithDeadline(ctx, start.Add(c.policy.Budget))
+	}
+
+	resp, err := c.retry(ctx, attemptCtx, start, operation)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The caller reads the body after Do returns, so the deadline must outlive it until then
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retry runs the attempts of Do, sending each with attemptCtx
+func (c *Client) retry(ctx, attemptCtx context.Context, start time.Time, operation func(ctx context.Context) (*http.Response, error)) (*http.Response, error) {
+	var lastErr error
+	policy := c.policy
+
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		// Fail fast while the circuit is open
+		if err := c.breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err := operation(attemptCtx)
+
+		// Check if we should retry
+		if err != nil {
+			if !IsTransientError(err) {
+				return nil, err
+			}
+			c.breaker.RecordFailure()
+			lastErr = err
+
+			// An attempt cut short by the budget leaves no time for another
+			if attemptCtx.Err() != nil && ctx.Err() == nil {
+				return nil, fmt.Errorf("%w: %v, retry budget of %v used up after %d attempts", domain.ErrLMStudioUnavailable, lastErr, policy.Budget, attempt)
+			}
+		} else if resp != nil {
+			// Check status code
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				c.breaker.RecordSuccess()
+				return resp, nil
+			}
+
+			if !policy.RetryableStatus(resp.StatusCode) {
+				c.breaker.RecordSuccess()
+				// Don't retry on other 4xx client errors
+				if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+					return nil, fmt.Errorf("%w: status %d - %s", domain.ErrInvalidRequest, resp.StatusCode, c.readErrorMessage(resp))
+				}
+				return resp, nil
+			}
+
+			// 429 means the server is up but busy, so only server errors count towards the circuit breaker
+			if resp.StatusCode == http.StatusTooManyRequests {
+				c.breaker.RecordSuccess()
+			} else {
+				c.breaker.RecordFailure()
+			}
+			lastErr = fmt.Errorf("server error: status %d - %s", resp.StatusCode, c.readErrorMessage(resp))
+		}
+
+		// Stop retrying once the failures have opened the circuit
+		if c.breaker.Status().State == domain.CircuitStateOpen {
+			return nil, fmt.Errorf("%w: %v, circuit breaker opened after %d attempts", domain.ErrLMStudioUnavailable, lastErr, attempt)
+		}
+
+		if attempt == policy.MaxAttempts {
+			break
+		}
+
+		// Wait with full jitter, or as long as Retry-After asks, within the retry budget
+		delay := policy.Delay(attempt, resp)
+		if policy.Budget > 0 && time.Since(start)+delay > policy.Budget {
+			return nil, fmt.Errorf("%w: %v, retry budget of %v used up after %d attempts", domain.ErrLMStudioUnavailable, lastErr, policy.Budget, attempt)
+		}
+		logrus.Warnf("%s request attempt %d/%d failed: %v, retrying in %v", c.name, attempt, policy.MaxAttempts, lastErr, delay)
+
+		// Check context before sleeping
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v after %d attempts", domain.ErrLMStudioUnavailable, lastErr, policy.MaxAttempts)
+}
+
+// DoOnce executes an operation without retrying, as streaming requests want immediate feedback
+// The circuit breaker still fails it fast and records the outcome. Transient errors and server
+// error responses wrap domain.ErrLMStudioUnavailable; other 4xx responses wrap domain.ErrInvalidRequest.
+func (c *Client) DoOnce(operation func() (*http.Response, error)) (*http.Response, error) {
+	// Fail fast while the circuit is open
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := operation()
+	if err != nil {
+		if IsTransientError(err) {
+			c.breaker.RecordFailure()
+			return nil, fmt.Errorf("%w: failed to send streaming request: %v", domain.ErrLMStudioUnavailable, err)
+		}
+		return nil, fmt.Errorf("failed to send streaming request: %w", err)
+	}
+
+	// Check response status; like in Do, only server errors count towards the circuit breaker
+	if resp.StatusCode >= 500 {
+		c.breaker.RecordFailure()
+	} else {
+		c.breaker.RecordSuccess()
+	}
+	if resp.StatusCode >= 400 {
+		message := c.readErrorMessage(resp)
+		if !c.policy.RetryableStatus(resp.StatusCode) {
+			return nil, fmt.Errorf("%w: status %d - %s", domain.ErrInvalidRequest, resp.StatusCode, message)
+		}
+		return nil, fmt.Errorf("%w: status %d - %s", domain.ErrLMStudioUnavailable, resp.StatusCode, message)
+	}
+
+	return resp, nil
+}
+
+// CircuitBreakerStatus returns the state of the circuit breaker guarding the server
+func (c *Client) CircuitBreakerStatus() domain.CircuitBreakerStatus {
+	return c.breaker.Status()
+}
+
+// cancelOnClose struct - Response body that releases the request context once closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the request context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// readErrorMessage reads and closes the body of a failed response, returning its error message
+func (c *Client) readErrorMessage(resp *http.Response) string {
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	return c.errorMessage(body)
+}
+
+// IsTransientError determines if a request error is transient and should be retried
+// Status codes are judged by the retry policy; this covers errors that got no response.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check for network-related errors
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return true
+		}
+	}
+
+	// Check for connection refused or other network issues
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	// Check for DNS errors
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	// Check for context deadline exceeded (timeout)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Check error message for common transient patterns
+	errMsg := err.Error()
+	transientPatterns := []string{
+		"connection refused",
+		"connection reset",
+		"no such host",
+		"network is unreachable",
+		"i/o timeout",
+		"EOF",
+	}
+	for _, pattern := range transientPatterns {
+		if strings.Contains(strings.ToLower(errMsg), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+
+	return false
+}
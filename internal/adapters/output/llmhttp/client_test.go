@@ -0,0 +1,217 @@
+package llmhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-template/internal/adapters/output/circuitbreaker"
+	"golang-template/internal/adapters/output/retry"
+	"golang-template/internal/domain"
+)
+
+// newTestClient creates a client with short retry delays for the given server
+func newTestClient(opts ...ClientOption) *Client {
+	policy := retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}
+	return NewClient("Test server", policy, circuitbreaker.NewCircuitBreaker("Test server", 10, time.Minute), opts...)
+}
+
+// get returns an operation that sends a GET request to url
+func get(url string) func(ctx context.Context) (*http.Response, error) {
+	return func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return http.DefaultClient.Do(req)
+	}
+}
+
+// TestDoRetriesServerErrors tests that 5xx responses are retried until one succeeds
+func TestDoRetriesServerErrors(t *testing.T) {
+	// Arrange
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requestCount, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := newTestClient()
+
+	// Act
+	resp, err := client.Do(context.Background(), get(server.URL))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error after retries, got: %v", err)
+	}
+	resp.Body.Close()
+	if count := atomic.LoadInt32(&requestCount); count != 3 {
+		t.Errorf("expected 3 requests, got: %d", count)
+	}
+	if status := client.CircuitBreakerStatus(); status.ConsecutiveFailures != 0 {
+		t.Errorf("expected the success to reset the circuit breaker, got: %+v", status)
+	}
+}
+
+// TestDoGivesUpAfterMaxAttempts tests that persistent server errors end in ErrLMStudioUnavailable
+func TestDoGivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := newTestClient().Do(context.Background(), get(server.URL))
+
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("expected ErrLMStudioUnavailable after 3 attempts, got: %v", err)
+	}
+}
+
+// TestDoFailsClientErrorsWithErrorMessage tests that 4xx responses are not retried and use the error message extractor
+func TestDoFailsClientErrorsWithErrorMessage(t *testing.T) {
+	// Arrange
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("model not found"))
+	}))
+	defer server.Close()
+	client := newTestClient(WithErrorMessage(func(body []byte) string { return "parsed: " + string(body) }))
+
+	// Act
+	_, err := client.Do(context.Background(), get(server.URL))
+
+	// Assert
+	if !errors.Is(err, domain.ErrInvalidRequest) || !strings.Contains(err.Error(), "parsed: model not found") {
+		t.Errorf("expected ErrInvalidRequest with the parsed message, got: %v", err)
+	}
+	if count := atomic.LoadInt32(&requestCount); count != 1 {
+		t.Errorf("expected no retry for 4xx, got %d requests", count)
+	}
+}
+
+// TestDoStopsHungAttemptAtBudget tests that an attempt is cut off when the retry budget runs out
+// instead of running until the HTTP client timeout
+func TestDoStopsHungAttemptAtBudget(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	policy := retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1, Budget: 200 * time.Millisecond}
+	client := NewClient("Test server", policy, circuitbreaker.NewCircuitBreaker("Test server", 10, time.Minute))
+
+	// Act
+	started := time.Now()
+	_, err := client.Do(context.Background(), get(server.URL))
+
+	// Assert
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) || !strings.Contains(err.Error(), "retry budget") {
+		t.Errorf("expected ErrLMStudioUnavailable for a used up retry budget, got: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("expected to give up at the 200ms budget, took %v", elapsed)
+	}
+}
+
+// TestDoKeepsBodyReadableWithinBudget tests that the budget deadline outlives Do until the body is closed
+func TestDoKeepsBodyReadableWithinBudget(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+	policy := retry.RetryPolicy{MaxAttempts: 1, Budget: time.Minute}
+	client := NewClient("Test server", policy, circuitbreaker.NewCircuitBreaker("Test server", 10, time.Minute))
+
+	// Act
+	resp, err := client.Do(context.Background(), get(server.URL))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	body, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// Assert
+	if readErr != nil || string(body) != "hello" {
+		t.Errorf("expected body hello, got %q (%v)", body, readErr)
+	}
+}
+
+// TestDoOnceMapsFailures tests that a single request maps failures without retrying
+func TestDoOnceMapsFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expected   error
+	}{
+		{"server error", http.StatusServiceUnavailable, domain.ErrLMStudioUnavailable},
+		{"client error", http.StatusBadRequest, domain.ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var requestCount int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requestCount, 1)
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			// Act
+			_, err := newTestClient().DoOnce(func() (*http.Response, error) {
+				return http.Get(server.URL)
+			})
+
+			// Assert
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got: %v", tt.expected, err)
+			}
+			if count := atomic.LoadInt32(&requestCount); count != 1 {
+				t.Errorf("expected a single request, got: %d", count)
+			}
+		})
+	}
+}
+
+// TestIsTransientError tests which request errors are worth retrying
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"dns", fmt.Errorf("lookup: %w", &net.DNSError{Err: "no such host", Name: "lmstudio"}), true},
+		{"deadline", fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"unexpected EOF", errors.New("unexpected EOF"), true},
+		{"cancelled", context.Canceled, false},
+		{"bad request", errors.New("invalid URL"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package llmhttp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ModelCache struct - Remembers the model used for requests that don't name one
+// The configured model is used if there is one, otherwise the first model the server lists.
+type ModelCache struct {
+	configModel string
+	serverName  string
+
+	mu          sync.RWMutex
+	cachedModel string
+}
+
+// NewModelCache func - Creates a model cache for the server called serverName in errors, e.g. "LM Studio"
+func NewModelCache(configModel, serverName string) *ModelCache {
+	return &ModelCache{
+		configModel: configModel,
+		serverName:  serverName,
+	}
+}
+
+// Get returns the model to use for requests, calling listModels to pick one on first use
+func (m *ModelCache) Get(ctx context.Context, listModels func(ctx context.Context) ([]domain.ModelInfo, error)) (string, error) {
+	// Fast path: check if model is already cached
+	m.mu.RLock()
+	if m.cachedModel != "" {
+		model := m.cachedModel
+		m.mu.RUnlock()
+		return model, nil
+	}
+	m.mu.RUnlock()
+
+	// Slow path: need to determine and cache the model
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Double-check after acquiring write lock
+	if m.cachedModel != "" {
+		return m.cachedModel, nil
+	}
+
+	// Check if model is configured via environment variable
+	if m.configModel != "" {
+		m.cachedModel = m.configModel
+		logrus.Infof("Using configured model from environment: %s", m.cachedModel)
+		return m.cachedModel, nil
+	}
+
+	// Query available models and select the first one
+	models, err := listModels(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get models for selection: %w", err)
+	}
+
+	if len(models) == 0 {
+		return "", fmt.Errorf("%w: no models available in %s", domain.ErrLMStudioUnavailable, m.serverName)
+	}
+
+	m.cachedModel = models[0].ID
+	logrus.Infof("Selected first available model: %s", m.cachedModel)
+
+	return m.cachedModel, nil
+}
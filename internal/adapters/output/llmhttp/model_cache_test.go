@@ -0,0 +1,60 @@
+package llmhttp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// TestModelCacheUsesConfiguredModel tests that a configured model is used without listing models
+func TestModelCacheUsesConfiguredModel(t *testing.T) {
+	cache := NewModelCache("configured-model", "Test server")
+
+	model, err := cache.Get(context.Background(), func(ctx context.Context) ([]domain.ModelInfo, error) {
+		t.Fatal("expected models not to be listed")
+		return nil, nil
+	})
+
+	if err != nil || model != "configured-model" {
+		t.Errorf("expected configured-model, got %q (%v)", model, err)
+	}
+}
+
+// TestModelCacheCachesFirstListedModel tests that the first listed model is picked once and reused
+func TestModelCacheCachesFirstListedModel(t *testing.T) {
+	// Arrange
+	cache := NewModelCache("", "Test server")
+	listCount := 0
+	listModels := func(ctx context.Context) ([]domain.ModelInfo, error) {
+		listCount++
+		return []domain.ModelInfo{{ID: "first-model"}, {ID: "second-model"}}, nil
+	}
+
+	// Act
+	first, _ := cache.Get(context.Background(), listModels)
+	second, _ := cache.Get(context.Background(), listModels)
+
+	// Assert
+	if first != "first-model" || second != "first-model" {
+		t.Errorf("expected first-model twice, got %q and %q", first, second)
+	}
+	if listCount != 1 {
+		t.Errorf("expected models to be listed once, got %d", listCount)
+	}
+}
+
+// TestModelCacheWithoutModels tests that a server without models is reported unavailable
+func TestModelCacheWithoutModels(t *testing.T) {
+	cache := NewModelCache("", "Test server")
+
+	_, err := cache.Get(context.Background(), func(ctx context.Context) ([]domain.ModelInfo, error) {
+		return nil, nil
+	})
+
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) || !strings.HasSuffix(err.Error(), "no models available in Test server") {
+		t.Errorf("expected ErrLMStudioUnavailable naming the server, got: %v", err)
+	}
+}
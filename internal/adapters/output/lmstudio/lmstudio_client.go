@@ -5,16 +5,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"golang-template/configs"
+	"golang-template/internal/adapters/output/circuitbreaker"
+	"golang-template/internal/adapters/output/llmhttp"
+	"golang-template/internal/adapters/output/retry"
 	"golang-template/internal/domain"
 
 	"github.com/sirupsen/logrus"
@@ -22,14 +22,15 @@ import (
 
 // LMStudioClientAdapter struct - Output adapter for LM Studio's OpenAI-compatible API
 type LMStudioClientAdapter struct {
-	httpClient  *http.Client
-	baseURL     string
-	configModel string
-	timeout     time.Duration
+	httpClient *http.Client
+	baseURL    string
+	timeout    time.Duration
+
+	// Retries transient failures, and fails requests fast while the server is down
+	client *llmhttp.Client
 
 	// Model caching
-	cachedModel string
-	modelMu     sync.RWMutex
+	models *llmhttp.ModelCache
 }
 
 // NewLMStudioClientAdapter func - Creates new LM Studio client adapter
@@ -60,11 +61,13 @@ func NewLMStudioClientAdapter(config configs.LMStudio) (*LMStudioClientAdapter,
 		},
 	}
 
+	breaker := circuitbreaker.NewCircuitBreaker("LM Studio at "+baseURL, config.CircuitBreakerThreshold, time.Duration(config.CircuitBreakerCooldown)*time.Second)
 	adapter := &LMStudioClientAdapter{
-		httpClient:  httpClient,
-		baseURL:     baseURL,
-		configModel: config.Model,
-		timeout:     timeout,
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		timeout:    timeout,
+		client:     llmhttp.NewClient("LM Studio", retry.NewRetryPolicy(config.Retry), breaker),
+		models:     llmhttp.NewModelCache(config.Model, "LM Studio"),
 	}
 
 	logrus.Infof("LM Studio client adapter initialized with base URL: %s, timeout: %v", baseURL, timeout)
@@ -72,150 +75,25 @@ func NewLMStudioClientAdapter(config configs.LMStudio) (*LMStudioClientAdapter,
 	return adapter, nil
 }
 
-// Retry configuration constants
-const (
-	maxRetryAttempts  = 30
-	initialDelay      = 1 * time.Second
-	maxDelay          = 30 * time.Second
-	backoffMultiplier = 2
-)
-
 // Streaming configuration constants
 const (
 	streamingChannelBufferSize = 100
 )
 
-// retryWithBackoff executes an operation with exponential backoff retry logic
-func (a *LMStudioClientAdapter) retryWithBackoff(ctx context.Context, operation func() (*http.Response, error)) (*http.Response, error) {
-	var lastErr error
-	delay := initialDelay
-
-	for attempt := 1; attempt <= maxRetryAttempts; attempt++ {
-		resp, err := operation()
-
-		// Check if we should retry
-		if err != nil {
-			if !a.isTransientError(err, 0) {
-				return nil, err
-			}
-			lastErr = err
-			logrus.Warnf("LM Studio request attempt %d/%d failed with error: %v, retrying in %v", attempt, maxRetryAttempts, err, delay)
-		} else if resp != nil {
-			// Check status code
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				return resp, nil
-			}
-
-			// Don't retry on 4xx client errors
-			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-				body, _ := io.ReadAll(resp.Body)
-				resp.Body.Close()
-				return nil, fmt.Errorf("%w: status %d - %s", domain.ErrInvalidRequest, resp.StatusCode, string(body))
-			}
-
-			// Retry on 5xx server errors
-			if a.isTransientError(nil, resp.StatusCode) {
-				body, _ := io.ReadAll(resp.Body)
-				resp.Body.Close()
-				lastErr = fmt.Errorf("server error: status %d - %s", resp.StatusCode, string(body))
-				logrus.Warnf("LM Studio request attempt %d/%d failed with status %d, retrying in %v", attempt, maxRetryAttempts, resp.StatusCode, delay)
-			} else {
-				return resp, nil
-			}
-		}
-
-		// Check context before sleeping
-		if attempt < maxRetryAttempts {
-			select {
-			case <-ctx.Done():
-				return nil, fmt.Errorf("context cancelled: %w", ctx.Err())
-			case <-time.After(delay):
-			}
-
-			// Calculate next delay with exponential backoff
-			delay = delay * backoffMultiplier
-			if delay > maxDelay {
-				delay = maxDelay
-			}
-		}
-	}
-
-	if lastErr != nil {
-		return nil, fmt.Errorf("%w: %v after %d attempts", domain.ErrLMStudioUnavailable, lastErr, maxRetryAttempts)
-	}
-	return nil, fmt.Errorf("%w: max retries exceeded", domain.ErrLMStudioUnavailable)
-}
-
-// isTransientError determines if an error or status code is transient and should be retried
-func (a *LMStudioClientAdapter) isTransientError(err error, statusCode int) bool {
-	// Check for transient status codes (5xx server errors)
-	if statusCode >= 500 && statusCode < 600 {
-		return true
-	}
-
-	// 4xx errors are NOT transient
-	if statusCode >= 400 && statusCode < 500 {
-		return false
-	}
-
-	if err == nil {
-		return false
-	}
-
-	// Check for network-related errors
-	var netErr net.Error
-	if errors.As(err, &netErr) {
-		if netErr.Timeout() {
-			return true
-		}
-	}
-
-	// Check for connection refused or other network issues
-	var opErr *net.OpError
-	if errors.As(err, &opErr) {
-		return true
-	}
-
-	// Check for DNS errors
-	var dnsErr *net.DNSError
-	if errors.As(err, &dnsErr) {
-		return true
-	}
-
-	// Check for context deadline exceeded (timeout)
-	if errors.Is(err, context.DeadlineExceeded) {
-		return true
-	}
-
-	// Check error message for common transient patterns
-	errMsg := err.Error()
-	transientPatterns := []string{
-		"connection refused",
-		"connection reset",
-		"no such host",
-		"network is unreachable",
-		"i/o timeout",
-		"EOF",
-	}
-	for _, pattern := range transientPatterns {
-		if strings.Contains(strings.ToLower(errMsg), strings.ToLower(pattern)) {
-			return true
-		}
-	}
-
-	return false
+// CircuitBreakerStatus returns the state of the circuit breaker guarding LM Studio requests
+func (a *LMStudioClientAdapter) CircuitBreakerStatus() domain.CircuitBreakerStatus {
+	return a.client.CircuitBreakerStatus()
 }
 
 // ListModels queries the /v1/models endpoint to retrieve available models from LM Studio
 func (a *LMStudioClientAdapter) ListModels(ctx context.Context) ([]domain.ModelInfo, error) {
 	url := fmt.Sprintf("%s/v1/models", a.baseURL)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create list models request: %w", err)
-	}
-
-	resp, err := a.retryWithBackoff(ctx, func() (*http.Response, error) {
+	resp, err := a.client.Do(ctx, func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create list models request: %w", err)
+		}
 		return a.httpClient.Do(req)
 	})
 	if err != nil {
@@ -246,45 +124,7 @@ func (a *LMStudioClientAdapter) ListModels(ctx context.Context) ([]domain.ModelI
 
 // getModel returns the model to use for requests, with caching
 func (a *LMStudioClientAdapter) getModel(ctx context.Context) (string, error) {
-	// Fast path: check if model is already cached
-	a.modelMu.RLock()
-	if a.cachedModel != "" {
-		model := a.cachedModel
-		a.modelMu.RUnlock()
-		return model, nil
-	}
-	a.modelMu.RUnlock()
-
-	// Slow path: need to determine and cache the model
-	a.modelMu.Lock()
-	defer a.modelMu.Unlock()
-
-	// Double-check after acquiring write lock
-	if a.cachedModel != "" {
-		return a.cachedModel, nil
-	}
-
-	// Check if model is configured via environment variable
-	if a.configModel != "" {
-		a.cachedModel = a.configModel
-		logrus.Infof("Using configured model from environment: %s", a.cachedModel)
-		return a.cachedModel, nil
-	}
-
-	// Query available models and select the first one
-	models, err := a.ListModels(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to get models for selection: %w", err)
-	}
-
-	if len(models) == 0 {
-		return "", fmt.Errorf("%w: no models available in LM Studio", domain.ErrLMStudioUnavailable)
-	}
-
-	a.cachedModel = models[0].ID
-	logrus.Infof("Selected first available model: %s", a.cachedModel)
-
-	return a.cachedModel, nil
+	return a.models.Get(ctx, a.ListModels)
 }
 
 // ChatCompletion sends a non-streaming chat completion request to LM Studio
@@ -303,15 +143,9 @@ func (a *LMStudioClientAdapter) ChatCompletion(ctx context.Context, request doma
 	// Build request body
 	reqBody := chatCompletionAPIRequest{
 		Model:    model,
-		Messages: make([]chatMessageAPI, len(request.Messages)),
+		Messages: toChatMessagesAPI(request.Messages),
 		Stream:   false,
-	}
-
-	for i, msg := range request.Messages {
-		reqBody.Messages[i] = chatMessageAPI{
-			Role:    string(msg.Role),
-			Content: msg.Content,
-		}
+		Tools:    toToolsAPI(request.Tools),
 	}
 
 	if request.Temperature != nil {
@@ -327,7 +161,7 @@ func (a *LMStudioClientAdapter) ChatCompletion(ctx context.Context, request doma
 	url := fmt.Sprintf("%s/v1/chat/completions", a.baseURL)
 
 	// Execute request with retry
-	resp, err := a.retryWithBackoff(ctx, func() (*http.Response, error) {
+	resp, err := a.client.Do(ctx, func(ctx context.Context) (*http.Response, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
 		if err != nil {
 			return nil, err
@@ -351,11 +185,12 @@ func (a *LMStudioClientAdapter) ChatCompletion(ctx context.Context, request doma
 		return nil, fmt.Errorf("no choices in response")
 	}
 
-	content := apiResp.Choices[0].Message.Content
+	message := apiResp.Choices[0].Message
 
 	// Build domain response
 	response := &domain.ChatCompletionResponse{
-		Content:          content,
+		Content:          message.Content,
+		ToolCalls:        fromToolCallsAPI(message.ToolCalls),
 		Model:            apiResp.Model,
 		PromptTokens:     apiResp.Usage.PromptTokens,
 		CompletionTokens: apiResp.Usage.CompletionTokens,
@@ -384,17 +219,10 @@ func (a *LMStudioClientAdapter) ChatCompletionStream(ctx context.Context, reques
 	// Build request body with stream=true
 	reqBody := chatCompletionAPIRequest{
 		Model:    model,
-		Messages: make([]chatMessageAPI, len(request.Messages)),
+		Messages: toChatMessagesAPI(request.Messages),
 		Stream:   true,
 	}
 
-	for i, msg := range request.Messages {
-		reqBody.Messages[i] = chatMessageAPI{
-			Role:    string(msg.Role),
-			Content: msg.Content,
-		}
-	}
-
 	if request.Temperature != nil {
 		reqBody.Temperature = request.Temperature
 	}
@@ -416,20 +244,11 @@ func (a *LMStudioClientAdapter) ChatCompletionStream(ctx context.Context, reques
 	req.Header.Set("Accept", "text/event-stream")
 
 	// Send request
-	resp, err := a.httpClient.Do(req)
+	resp, err := a.client.DoOnce(func() (*http.Response, error) {
+		return a.httpClient.Do(req)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send streaming request: %w", err)
-	}
-
-	// Check response status
-	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
-
-		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-			return nil, fmt.Errorf("%w: status %d - %s", domain.ErrInvalidRequest, resp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("%w: status %d - %s", domain.ErrLMStudioUnavailable, resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	// Create buffered channel for chunks
@@ -585,9 +404,141 @@ func (a *LMStudioClientAdapter) parseSSELine(line string) (*domain.ChatCompletio
 // API request/response structures for LM Studio's OpenAI-compatible API
 
 // chatMessageAPI represents a message in the API request
+// Content is either a string or, for multimodal messages, a []contentPartAPI.
 type chatMessageAPI struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string        `json:"role"`
+	Content    interface{}   `json:"content"`
+	ToolCalls  []toolCallAPI `json:"tool_calls,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
+}
+
+// toolAPI represents a function tool offered to the model
+type toolAPI struct {
+	Type     string          `json:"type"`
+	Function toolFunctionAPI `json:"function"`
+}
+
+// toolFunctionAPI represents the definition of a function tool
+type toolFunctionAPI struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// toolCallAPI represents a function call made by the model
+type toolCallAPI struct {
+	ID       string              `json:"id"`
+	Type     string              `json:"type"`
+	Function toolCallFunctionAPI `json:"function"`
+}
+
+// toolCallFunctionAPI represents the function name and JSON arguments of a tool call
+type toolCallFunctionAPI struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// contentPartAPI represents one part of a multimodal message content array
+type contentPartAPI struct {
+	Type     string       `json:"type"`
+	Text     string       `json:"text,omitempty"`
+	ImageURL *imageURLAPI `json:"image_url,omitempty"`
+}
+
+// imageURLAPI represents the image reference of an image_url content part
+type imageURLAPI struct {
+	URL string `json:"url"`
+}
+
+// toChatMessagesAPI converts domain chat messages to API messages
+// Messages with content parts are sent as OpenAI-style content arrays, others as plain strings.
+func toChatMessagesAPI(messages []domain.ChatMessage) []chatMessageAPI {
+	apiMessages := make([]chatMessageAPI, len(messages))
+
+	for i, msg := range messages {
+		apiMessages[i] = chatMessageAPI{
+			Role:       string(msg.Role),
+			Content:    msg.Content,
+			ToolCalls:  toToolCallsAPI(msg.ToolCalls),
+			ToolCallID: msg.ToolCallID,
+		}
+
+		if len(msg.Parts) == 0 {
+			continue
+		}
+
+		parts := make([]contentPartAPI, 0, len(msg.Parts))
+		for _, part := range msg.Parts {
+			switch part.Type {
+			case domain.ChatContentPartTypeText:
+				parts = append(parts, contentPartAPI{Type: string(part.Type), Text: part.Text})
+			case domain.ChatContentPartTypeImageURL:
+				parts = append(parts, contentPartAPI{Type: string(part.Type), ImageURL: &imageURLAPI{URL: part.ImageURL}})
+			default:
+				logrus.Warnf("Skipping unsupported chat content part type: %s", part.Type)
+			}
+		}
+		apiMessages[i].Content = parts
+	}
+
+	return apiMessages
+}
+
+// toToolsAPI converts domain tool definitions to API function tools
+func toToolsAPI(tools []domain.ChatToolDefinition) []toolAPI {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	apiTools := make([]toolAPI, len(tools))
+	for i, tool := range tools {
+		apiTools[i] = toolAPI{
+			Type: "function",
+			Function: toolFunctionAPI{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return apiTools
+}
+
+// toToolCallsAPI converts domain tool calls to API tool calls
+func toToolCallsAPI(calls []domain.ChatToolCall) []toolCallAPI {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	apiCalls := make([]toolCallAPI, len(calls))
+	for i, call := range calls {
+		apiCalls[i] = toolCallAPI{
+			ID:   call.ID,
+			Type: "function",
+			Function: toolCallFunctionAPI{
+				Name:      call.Name,
+				Arguments: call.Arguments,
+			},
+		}
+	}
+	return apiCalls
+}
+
+// fromToolCallsAPI converts API tool calls to domain tool calls
+func fromToolCallsAPI(calls []toolCallAPI) []domain.ChatToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	domainCalls := make([]domain.ChatToolCall, len(calls))
+	for i, call := range calls {
+		domainCalls[i] = domain.ChatToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		}
+	}
+	return domainCalls
 }
 
 // chatCompletionAPIRequest represents the request body for chat completions
@@ -596,6 +547,7 @@ type chatCompletionAPIRequest struct {
 	Messages    []chatMessageAPI `json:"messages"`
 	Stream      bool             `json:"stream"`
 	Temperature *float64         `json:"temperature,omitempty"`
+	Tools       []toolAPI        `json:"tools,omitempty"`
 }
 
 // chatCompletionAPIResponse represents the response from non-streaming chat completions
@@ -607,8 +559,9 @@ type chatCompletionAPIResponse struct {
 	Choices []struct {
 		Index   int `json:"index"`
 		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
+			Role      string        `json:"role"`
+			Content   string        `json:"content"`
+			ToolCalls []toolCallAPI `json:"tool_calls,omitempty"`
 		} `json:"message"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
@@ -3,6 +3,7 @@ package lmstudio
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -36,8 +37,9 @@ func TestNewLMStudioClientAdapterWithConfig(t *testing.T) {
 		t.Errorf("expected baseURL to be http://localhost:5678, got: %s", adapter.baseURL)
 	}
 
-	if adapter.configModel != "test-model" {
-		t.Errorf("expected configModel to be test-model, got: %s", adapter.configModel)
+	// The configured model is used without asking the server
+	if model, err := adapter.getModel(context.Background()); err != nil || model != "test-model" {
+		t.Errorf("expected model to be test-model, got: %s (%v)", model, err)
 	}
 
 	if adapter.timeout != 30*time.Second {
@@ -116,16 +118,18 @@ func TestChatCompletionSuccess(t *testing.T) {
 			Choices: []struct {
 				Index   int `json:"index"`
 				Message struct {
-					Role    string `json:"role"`
-					Content string `json:"content"`
+					Role      string        `json:"role"`
+					Content   string        `json:"content"`
+					ToolCalls []toolCallAPI `json:"tool_calls,omitempty"`
 				} `json:"message"`
 				FinishReason string `json:"finish_reason"`
 			}{
 				{
 					Index: 0,
 					Message: struct {
-						Role    string `json:"role"`
-						Content string `json:"content"`
+						Role      string        `json:"role"`
+						Content   string        `json:"content"`
+						ToolCalls []toolCallAPI `json:"tool_calls,omitempty"`
 					}{
 						Role:    "assistant",
 						Content: "Hello! How can I help you today?",
@@ -515,3 +519,292 @@ func TestNoRetryFor4xxErrors(t *testing.T) {
 		t.Errorf("expected error to contain 'invalid request', got: %v", err)
 	}
 }
+
+// TestChatCompletionMultimodalContent tests that messages with content parts are sent as
+// OpenAI-style content arrays while plain messages stay strings
+func TestChatCompletionMultimodalContent(t *testing.T) {
+	var rawMessages []map[string]json.RawMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody struct {
+			Messages []map[string]json.RawMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		rawMessages = reqBody.Messages
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model":"test-model","choices":[{"message":{"role":"assistant","content":"A cat."}}]}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewLMStudioClientAdapter(configs.LMStudio{BaseURL: server.URL, Model: "test-model", Timeout: 30})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	request := domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{
+			{Role: domain.ChatMessageRoleSystem, Content: "You are helpful"},
+			{
+				Role:    domain.ChatMessageRoleUser,
+				Content: "What is this?",
+				Parts: []domain.ChatContentPart{
+					{Type: domain.ChatContentPartTypeText, Text: "What is this?"},
+					{Type: domain.ChatContentPartTypeImageURL, ImageURL: "data:image/jpeg;base64,AAAA"},
+				},
+			},
+		},
+	}
+
+	if _, err := adapter.ChatCompletion(context.Background(), request); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(rawMessages) != 2 {
+		t.Fatalf("expected 2 messages, got: %d", len(rawMessages))
+	}
+
+	if string(rawMessages[0]["content"]) != `"You are helpful"` {
+		t.Errorf("expected plain string content, got: %s", rawMessages[0]["content"])
+	}
+
+	var parts []struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		ImageURL struct {
+			URL string `json:"url"`
+		} `json:"image_url"`
+	}
+	if err := json.Unmarshal(rawMessages[1]["content"], &parts); err != nil {
+		t.Fatalf("expected content array, got: %s", rawMessages[1]["content"])
+	}
+	if len(parts) != 2 || parts[0].Type != "text" || parts[0].Text != "What is this?" {
+		t.Errorf("unexpected text part: %+v", parts)
+	}
+	if parts[1].Type != "image_url" || parts[1].ImageURL.URL != "data:image/jpeg;base64,AAAA" {
+		t.Errorf("unexpected image part: %+v", parts)
+	}
+}
+
+// TestChatCompletionToolCalls tests that tools and tool messages are sent in OpenAI format
+// and that tool calls in the response are returned to the caller
+func TestChatCompletionToolCalls(t *testing.T) {
+	var reqBody struct {
+		Tools []struct {
+			Type     string `json:"type"`
+			Function struct {
+				Name       string          `json:"name"`
+				Parameters json.RawMessage `json:"parameters"`
+			} `json:"function"`
+		} `json:"tools"`
+		Messages []struct {
+			Role       string `json:"role"`
+			ToolCallID string `json:"tool_call_id"`
+			ToolCalls  []struct {
+				ID       string `json:"id"`
+				Type     string `json:"type"`
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"messages"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model":"test-model","choices":[{"message":{"role":"assistant","content":"","tool_calls":[`+
+			`{"id":"call_2","type":"function","function":{"name":"list_todos","arguments":"{\"limit\":3}"}}]},"finish_reason":"tool_calls"}]}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewLMStudioClientAdapter(configs.LMStudio{BaseURL: server.URL, Model: "test-model", Timeout: 30})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	request := domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{
+			{Role: domain.ChatMessageRoleUser, Content: "Add milk"},
+			{Role: domain.ChatMessageRoleAssistant, ToolCalls: []domain.ChatToolCall{{ID: "call_1", Name: "create_todo", Arguments: `{"title":"milk"}`}}},
+			{Role: domain.ChatMessageRoleTool, ToolCallID: "call_1", Content: `{"id":"1"}`},
+		},
+		Tools: []domain.ChatToolDefinition{
+			{Name: "create_todo", Description: "Create a todo", Parameters: json.RawMessage(`{"type":"object"}`)},
+		},
+	}
+
+	response, err := adapter.ChatCompletion(context.Background(), request)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(reqBody.Tools) != 1 || reqBody.Tools[0].Type != "function" || reqBody.Tools[0].Function.Name != "create_todo" {
+		t.Errorf("expected create_todo function tool, got: %+v", reqBody.Tools)
+	}
+	if string(reqBody.Tools[0].Function.Parameters) != `{"type":"object"}` {
+		t.Errorf("expected parameters schema to be sent as is, got: %s", reqBody.Tools[0].Function.Parameters)
+	}
+	if calls := reqBody.Messages[1].ToolCalls; len(calls) != 1 || calls[0].ID != "call_1" || calls[0].Type != "function" || calls[0].Function.Name != "create_todo" {
+		t.Errorf("expected assistant tool call, got: %+v", calls)
+	}
+	if reqBody.Messages[2].Role != "tool" || reqBody.Messages[2].ToolCallID != "call_1" {
+		t.Errorf("expected tool result message, got: %+v", reqBody.Messages[2])
+	}
+
+	if len(response.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got: %+v", response.ToolCalls)
+	}
+	if call := response.ToolCalls[0]; call.ID != "call_2" || call.Name != "list_todos" || call.Arguments != `{"limit":3}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+}
+
+// TestChatCompletionStreamConnectionRefused tests that an unreachable server is reported as unavailable
+func TestChatCompletionStreamConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	baseURL := server.URL
+	server.Close()
+
+	adapter, _ := NewLMStudioClientAdapter(configs.LMStudio{BaseURL: baseURL, Model: "test-model", Timeout: 5})
+
+	_, err := adapter.ChatCompletionStream(context.Background(), domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Hello"}},
+	})
+
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable, got: %v", err)
+	}
+}
+
+// TestCircuitBreakerFailsFast tests that the circuit opens after consecutive failures and
+// later requests fail fast without reaching LM Studio
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var requestCount int32
+
+	// Create mock server that always returns 503 Service Unavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := configs.LMStudio{
+		BaseURL:                 server.URL,
+		Model:                   "test-model",
+		Timeout:                 30,
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerCooldown:  60,
+	}
+
+	adapter, err := NewLMStudioClientAdapter(config)
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	// First request retries until the circuit opens, instead of for all 30 attempts
+	_, err = adapter.ListModels(context.Background())
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Fatalf("expected ErrLMStudioUnavailable, got: %v", err)
+	}
+	if count := atomic.LoadInt32(&requestCount); count != 2 {
+		t.Errorf("expected 2 requests before the circuit opened, got: %d", count)
+	}
+
+	// Later requests fail fast, streaming included
+	request := domain.ChatCompletionRequest{Messages: []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Hello"}}}
+	if _, err := adapter.ChatCompletion(context.Background(), request); !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable, got: %v", err)
+	}
+	if _, err := adapter.ChatCompletionStream(context.Background(), request); !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable for streaming, got: %v", err)
+	}
+	if count := atomic.LoadInt32(&requestCount); count != 2 {
+		t.Errorf("expected no requests while the circuit is open, got: %d", count)
+	}
+
+	status := adapter.CircuitBreakerStatus()
+	if status.State != domain.CircuitStateOpen || status.ConsecutiveFailures != 2 {
+		t.Errorf("expected an open circuit after 2 failures, got: %+v", status)
+	}
+}
+
+// TestRetryOn429HonorsRetryAfter tests that 429 is retried after the delay the server asks for
+func TestRetryOn429HonorsRetryAfter(t *testing.T) {
+	var requestCount int32
+	var firstRequest, secondRequest time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requestCount, 1) == 1 {
+			firstRequest = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		secondRequest = time.Now()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[{"id":"test-model","object":"model","owned_by":"lmstudio"}]}`))
+	}))
+	defer server.Close()
+
+	// A tiny backoff shows the wait comes from Retry-After
+	adapter, err := NewLMStudioClientAdapter(configs.LMStudio{BaseURL: server.URL, Timeout: 30, Retry: configs.LMStudioRetry{InitialDelayMs: 1}})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	models, err := adapter.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error after retry, got: %v", err)
+	}
+
+	if len(models) != 1 || atomic.LoadInt32(&requestCount) != 2 {
+		t.Errorf("expected 1 model after 2 requests, got %d models after %d requests", len(models), requestCount)
+	}
+	if waited := secondRequest.Sub(firstRequest); waited < time.Second {
+		t.Errorf("expected the retry to wait for Retry-After of 1s, waited %v", waited)
+	}
+	if adapter.CircuitBreakerStatus().ConsecutiveFailures != 0 {
+		t.Error("expected 429 not to count towards the circuit breaker")
+	}
+}
+
+// TestRetryBudgetStopsRetrying tests that a request gives up once its retry budget would be exceeded
+func TestRetryBudgetStopsRetrying(t *testing.T) {
+	var requestCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Retry-After delays of 1s fit once into the 2s budget
+	adapter, err := NewLMStudioClientAdapter(configs.LMStudio{
+		BaseURL: server.URL,
+		Timeout: 30,
+		Retry:   configs.LMStudioRetry{MaxAttempts: 10, Budget: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	started := time.Now()
+	_, err = adapter.ListModels(context.Background())
+
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) || !strings.Contains(err.Error(), "retry budget") {
+		t.Errorf("expected ErrLMStudioUnavailable for a used up retry budget, got: %v", err)
+	}
+	if count := atomic.LoadInt32(&requestCount); count != 2 {
+		t.Errorf("expected 2 requests within the budget, got: %d", count)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("expected to give up within the 2s budget, took %v", elapsed)
+	}
+}
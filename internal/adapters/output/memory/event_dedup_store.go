@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"golang-template/internal/ports/output"
+)
+
+// Compile-time check to ensure MemoryEventDedupStore implements EventDedupStore interface
+var _ output.EventDedupStore = (*MemoryEventDedupStore)(nil)
+
+// MemoryEventDedupStore struct - Output adapter for in-memory webhook event deduplication
+// Event IDs are kept for the configured TTL. Expired entries are swept lazily, at most once per TTL.
+type MemoryEventDedupStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+// NewMemoryEventDedupStore creates a new in-memory dedup store.
+// ttl: Duration for which an event ID is remembered
+func NewMemoryEventDedupStore(ttl time.Duration) *MemoryEventDedupStore {
+	return &MemoryEventDedupStore{
+		seen:      make(map[string]time.Time),
+		ttl:       ttl,
+		lastSweep: time.Now(),
+	}
+}
+
+// MarkSeen records the event ID and reports whether it was already recorded within the TTL.
+func (m *MemoryEventDedupStore) MarkSeen(eventID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	if seenAt, exists := m.seen[eventID]; exists && now.Sub(seenAt) <= m.ttl {
+		return true, nil
+	}
+
+	m.seen[eventID] = now
+	return false, nil
+}
+
+// sweep removes expired event IDs. Must be called with the lock held.
+func (m *MemoryEventDedupStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < m.ttl {
+		return
+	}
+
+	for eventID, seenAt := range m.seen {
+		if now.Sub(seenAt) > m.ttl {
+			delete(m.seen, eventID)
+		}
+	}
+	m.lastSweep = now
+}
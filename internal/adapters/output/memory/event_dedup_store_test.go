@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMarkSeenReportsFirstAndRepeatedEvents tests that the first MarkSeen returns false
+// and subsequent calls for the same ID return true
+func TestMarkSeenReportsFirstAndRepeatedEvents(t *testing.T) {
+	store := NewMemoryEventDedupStore(time.Hour)
+
+	seen, err := store.MarkSeen("01HXYZEVENT")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if seen {
+		t.Error("expected first MarkSeen to report unseen")
+	}
+
+	seen, _ = store.MarkSeen("01HXYZEVENT")
+	if !seen {
+		t.Error("expected second MarkSeen to report seen")
+	}
+
+	seen, _ = store.MarkSeen("01HXYZOTHER")
+	if seen {
+		t.Error("expected a different event ID to report unseen")
+	}
+}
+
+// TestMarkSeenForgetsExpiredEvents tests that event IDs are forgotten after the TTL
+func TestMarkSeenForgetsExpiredEvents(t *testing.T) {
+	store := NewMemoryEventDedupStore(time.Hour)
+
+	_, _ = store.MarkSeen("01HXYZEVENT")
+
+	// Simulate the entry and last sweep being older than the TTL
+	store.seen["01HXYZEVENT"] = time.Now().Add(-2 * time.Hour)
+	store.lastSweep = time.Now().Add(-2 * time.Hour)
+
+	seen, _ := store.MarkSeen("01HXYZEVENT")
+	if seen {
+		t.Error("expected expired event ID to report unseen")
+	}
+
+	if len(store.seen) != 1 {
+		t.Errorf("expected sweep to leave only the re-recorded ID, got %d entries", len(store.seen))
+	}
+}
+
+// TestMarkSeenConcurrentAccess tests that exactly one concurrent caller sees an ID as new
+func TestMarkSeenConcurrentAccess(t *testing.T) {
+	store := NewMemoryEventDedupStore(time.Hour)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	firstSeen := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seen, _ := store.MarkSeen("01HXYZEVENT")
+			if !seen {
+				mu.Lock()
+				firstSeen++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstSeen != 1 {
+		t.Errorf("expected exactly one caller to see the event as new, got %d", firstSeen)
+	}
+}
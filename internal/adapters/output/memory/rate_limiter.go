@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"golang-template/internal/ports/output"
+)
+
+// Compile-time check to ensure MemoryRateLimiter implements RateLimiter interface
+var _ output.RateLimiter = (*MemoryRateLimiter)(nil)
+
+// tokenBucket tracks the remaining allowance of a single key
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// MemoryRateLimiter struct - Output adapter for in-memory token bucket rate limiting
+// Each key has a bucket of burst tokens refilled at rate tokens per minute. Buckets that
+// have refilled completely carry no state and are swept lazily, at most once per refill period.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	rate      float64 // tokens per second
+	burst     float64
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+// NewMemoryRateLimiter creates a new in-memory rate limiter.
+// perMinute: Number of requests a key may make per minute once its burst is used up
+// burst: Number of requests a key may make in quick succession
+func NewMemoryRateLimiter(perMinute, burst int) *MemoryRateLimiter {
+	if perMinute <= 0 {
+		perMinute = 1
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		rate:      float64(perMinute) / 60,
+		burst:     float64(burst),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow consumes one token from the key's bucket and reports whether one was available.
+func (m *MemoryRateLimiter) Allow(key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	bucket, exists := m.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: m.burst, lastRefill: now}
+		m.buckets[key] = bucket
+	} else {
+		m.refill(bucket, now)
+	}
+
+	if bucket.tokens < 1 {
+		return false, nil
+	}
+
+	bucket.tokens--
+	return true, nil
+}
+
+// refill adds the tokens earned since the last refill. Must be called with the lock held.
+func (m *MemoryRateLimiter) refill(bucket *tokenBucket, now time.Time) {
+	bucket.tokens += now.Sub(bucket.lastRefill).Seconds() * m.rate
+	if bucket.tokens > m.burst {
+		bucket.tokens = m.burst
+	}
+	bucket.lastRefill = now
+}
+
+// sweep removes buckets that have refilled completely. Must be called with the lock held.
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	fullRefill := time.Duration(m.burst / m.rate * float64(time.Second))
+	if now.Sub(m.lastSweep) < fullRefill {
+		return
+	}
+
+	for key, bucket := range m.buckets {
+		m.refill(bucket, now)
+		if bucket.tokens >= m.burst {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAllowPermitsBurstThenLimits tests that a key may make burst requests before being limited
+func TestAllowPermitsBurstThenLimits(t *testing.T) {
+	limiter := NewMemoryRateLimiter(60, 3)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		allowed, err := limiter.Allow("user-a")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if !allowed {
+			t.Fatalf("expected request %d to be allowed within the burst", i+1)
+		}
+	}
+
+	if allowed, _ := limiter.Allow("user-a"); allowed {
+		t.Error("expected request beyond the burst to be limited")
+	}
+
+	// Other keys have their own bucket
+	if allowed, _ := limiter.Allow("user-b"); !allowed {
+		t.Error("expected a different key to be allowed")
+	}
+}
+
+// TestAllowRefillsOverTime tests that tokens are refilled at the configured rate
+func TestAllowRefillsOverTime(t *testing.T) {
+	limiter := NewMemoryRateLimiter(60, 1)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	_, _ = limiter.Allow("user-a")
+	if allowed, _ := limiter.Allow("user-a"); allowed {
+		t.Fatal("expected second request to be limited")
+	}
+
+	// 60 per minute refills one token per second
+	now = now.Add(500 * time.Millisecond)
+	if allowed, _ := limiter.Allow("user-a"); allowed {
+		t.Error("expected request to be limited before a full token is refilled")
+	}
+
+	now = now.Add(time.Second)
+	if allowed, _ := limiter.Allow("user-a"); !allowed {
+		t.Error("expected request to be allowed after the token is refilled")
+	}
+}
+
+// TestAllowSweepsRefilledBuckets tests that idle buckets are removed once refilled
+func TestAllowSweepsRefilledBuckets(t *testing.T) {
+	limiter := NewMemoryRateLimiter(60, 2)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	_, _ = limiter.Allow("user-idle")
+
+	now = now.Add(time.Minute)
+	_, _ = limiter.Allow("user-active")
+
+	if _, exists := limiter.buckets["user-idle"]; exists {
+		t.Error("expected refilled idle bucket to be swept")
+	}
+	if _, exists := limiter.buckets["user-active"]; !exists {
+		t.Error("expected active bucket to be kept")
+	}
+}
@@ -1,33 +1,84 @@
 package memory
 
 import (
+	"container/list"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang-template/internal/domain"
 	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
 )
 
-// Compile-time check to ensure MemorySessionStore implements SessionStore interface
-var _ output.SessionStore = (*MemorySessionStore)(nil)
+// Compile-time checks to ensure MemorySessionStore implements SessionStore and SessionCounter interfaces
+var (
+	_ output.SessionStore   = (*MemorySessionStore)(nil)
+	_ output.SessionCounter = (*MemorySessionStore)(nil)
+)
 
 // MemorySessionStore struct - Output adapter for in-memory session storage
-// Uses sync.Map for thread-safe concurrent access to conversation sessions.
+// Sessions are kept in a map guarded by a mutex plus a recency list, so the least recently
+// used session can be evicted when the optional session cap is reached.
 // Stores session configuration for timeout and maxTurns to be used when creating new sessions.
+// Expired sessions are removed lazily in GetSession and by the janitor started with StartJanitor.
+// The store keeps its own copies of sessions, so callers never share a session with the janitor.
 type MemorySessionStore struct {
-	sessions sync.Map
+	mu       sync.Mutex
+	sessions map[string]*list.Element
+	lru      *list.List // front = most recently used
 	timeout  time.Duration
 	maxTurns int
+
+	// maxSessions caps the number of stored sessions, 0 means unlimited
+	maxSessions int
+
+	expiredEvictions  atomic.Uint64
+	capacityEvictions atomic.Uint64
+
+	janitorMu   sync.Mutex
+	janitorStop chan struct{}
+	janitorDone chan struct{}
+}
+
+// MemorySessionStoreOption func - Functional option for configuring MemorySessionStore
+type MemorySessionStoreOption func(*MemorySessionStore)
+
+// WithMaxSessions func - Caps the number of stored sessions, evicting the least recently used one when full
+// A value of 0 or less means unlimited.
+func WithMaxSessions(maxSessions int) MemorySessionStoreOption {
+	return func(m *MemorySessionStore) {
+		if maxSessions > 0 {
+			m.maxSessions = maxSessions
+		}
+	}
+}
+
+// SessionStoreStats struct - Snapshot of the in-memory session store counters
+type SessionStoreStats struct {
+	Sessions          int
+	ExpiredEvictions  uint64
+	CapacityEvictions uint64
 }
 
 // NewMemorySessionStore creates a new in-memory session store with configurable session parameters.
 // timeout: Duration after which sessions expire
 // maxTurns: Maximum number of conversation turns to retain in session history
-func NewMemorySessionStore(timeout time.Duration, maxTurns int) *MemorySessionStore {
-	return &MemorySessionStore{
+func NewMemorySessionStore(timeout time.Duration, maxTurns int, opts ...MemorySessionStoreOption) *MemorySessionStore {
+	m := &MemorySessionStore{
+		sessions: make(map[string]*list.Element),
+		lru:      list.New(),
 		timeout:  timeout,
 		maxTurns: maxTurns,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 // GetTimeout returns the configured session timeout duration.
@@ -45,41 +96,46 @@ func (m *MemorySessionStore) GetMaxTurns() int {
 // GetSession retrieves a conversation session by LINE user ID.
 // Returns the session if found and not expired, or nil if the session
 // does not exist or has expired. Expired sessions are deleted (lazy cleanup).
-// LastAccessTime is updated for valid sessions.
+// LastAccessTime is updated for valid sessions. The returned session is a copy owned by the caller.
 func (m *MemorySessionStore) GetSession(userID string) (*domain.ConversationSession, error) {
-	value, exists := m.sessions.Load(userID)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, exists := m.sessions[userID]
 	if !exists {
 		return nil, nil
 	}
 
-	session, ok := value.(*domain.ConversationSession)
-	if !ok {
-		// If data is malformed, delete and return nil
-		m.sessions.Delete(userID)
-		return nil, nil
-	}
+	session := element.Value.(*domain.ConversationSession)
 
 	// Check if session is expired
 	if session.IsExpired() {
 		// Lazy cleanup: delete expired session
-		m.sessions.Delete(userID)
+		m.remove(userID, element)
+		m.expiredEvictions.Add(1)
 		return nil, nil
 	}
 
 	// Update LastAccessTime for valid session
 	session.LastAccessTime = time.Now()
+	m.lru.MoveToFront(element)
 
-	return session, nil
+	return cloneSession(session), nil
 }
 
 // UpdateSession creates or updates a conversation session.
 // The session's LastAccessTime is updated to the current time before storing.
+// A copy of the session is stored, so the caller may keep using it.
+// If the store is at its session cap, the least recently used session is evicted.
 func (m *MemorySessionStore) UpdateSession(session *domain.ConversationSession) error {
 	// Update LastAccessTime
 	session.LastAccessTime = time.Now()
+	stored := cloneSession(session)
 
-	// Store session with userID as key
-	m.sessions.Store(session.UserID, session)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.put(stored)
 
 	return nil
 }
@@ -87,6 +143,138 @@ func (m *MemorySessionStore) UpdateSession(session *domain.ConversationSession)
 // DeleteSession removes a conversation session by LINE user ID.
 // This operation is idempotent - deleting a non-existent session does not return an error.
 func (m *MemorySessionStore) DeleteSession(userID string) error {
-	m.sessions.Delete(userID)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, exists := m.sessions[userID]; exists {
+		m.remove(userID, element)
+	}
 	return nil
 }
+
+// CountSessions returns the number of sessions that have not expired.
+func (m *MemorySessionStore) CountSessions() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, element := range m.sessions {
+		if !element.Value.(*domain.ConversationSession).IsExpired() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Stats returns the current number of sessions and eviction counters.
+func (m *MemorySessionStore) Stats() SessionStoreStats {
+	m.mu.Lock()
+	sessions := len(m.sessions)
+	m.mu.Unlock()
+
+	return SessionStoreStats{
+		Sessions:          sessions,
+		ExpiredEvictions:  m.expiredEvictions.Load(),
+		CapacityEvictions: m.capacityEvictions.Load(),
+	}
+}
+
+// StartJanitor starts a background goroutine that removes expired sessions every interval.
+// Calling StartJanitor on a store whose janitor is already running has no effect.
+func (m *MemorySessionStore) StartJanitor(interval time.Duration) {
+	m.janitorMu.Lock()
+	defer m.janitorMu.Unlock()
+
+	if m.janitorStop != nil || interval <= 0 {
+		return
+	}
+
+	m.janitorStop = make(chan struct{})
+	m.janitorDone = make(chan struct{})
+
+	go m.runJanitor(interval, m.janitorStop, m.janitorDone)
+}
+
+// StopJanitor stops the background janitor and waits for it to exit.
+// This operation is idempotent.
+func (m *MemorySessionStore) StopJanitor() {
+	m.janitorMu.Lock()
+	defer m.janitorMu.Unlock()
+
+	if m.janitorStop == nil {
+		return
+	}
+
+	close(m.janitorStop)
+	<-m.janitorDone
+	m.janitorStop = nil
+	m.janitorDone = nil
+}
+
+// runJanitor - Sweeps expired sessions on every tick until stop is closed
+func (m *MemorySessionStore) runJanitor(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			removed := m.sweepExpired()
+			stats := m.Stats()
+			logrus.Infof("Session janitor removed %d expired sessions: sessions=%d, expiredEvictions=%d, capacityEvictions=%d",
+				removed, stats.Sessions, stats.ExpiredEvictions, stats.CapacityEvictions)
+		}
+	}
+}
+
+// sweepExpired removes all expired sessions and returns how many were removed
+func (m *MemorySessionStore) sweepExpired() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+	for userID, element := range m.sessions {
+		if element.Value.(*domain.ConversationSession).IsExpired() {
+			m.remove(userID, element)
+			removed++
+		}
+	}
+	m.expiredEvictions.Add(uint64(removed))
+
+	return removed
+}
+
+// put stores the session as most recently used, evicting the least recently used
+// sessions beyond the cap. Must be called with the lock held.
+func (m *MemorySessionStore) put(session *domain.ConversationSession) {
+	if element, exists := m.sessions[session.UserID]; exists {
+		element.Value = session
+		m.lru.MoveToFront(element)
+		return
+	}
+
+	m.sessions[session.UserID] = m.lru.PushFront(session)
+
+	for m.maxSessions > 0 && len(m.sessions) > m.maxSessions {
+		oldest := m.lru.Back()
+		m.remove(oldest.Value.(*domain.ConversationSession).UserID, oldest)
+		m.capacityEvictions.Add(1)
+	}
+}
+
+// cloneSession returns a copy of the session that shares no mutable state with it
+func cloneSession(session *domain.ConversationSession) *domain.ConversationSession {
+	clone := *session
+	clone.Messages = slices.Clone(session.Messages)
+	return &clone
+}
+
+// remove deletes a session from the map and recency list. Must be called with the lock held.
+func (m *MemorySessionStore) remove(userID string, element *list.Element) {
+	m.lru.Remove(element)
+	delete(m.sessions, userID)
+}
@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"golang-template/internal/adapters/output/sessionstoretest"
 	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
 )
 
 // Default test configuration values
@@ -54,8 +56,8 @@ func TestGetSessionReturnsNilForExpiredSessionWithConfiguredTimeout(t *testing.T
 	// Manually set LastAccessTime to simulate an expired session
 	session.LastAccessTime = time.Now().Add(-6 * time.Minute)
 
-	// Store directly in sync.Map to bypass UpdateSession's LastAccessTime update
-	store.sessions.Store("U1234567890abcdef", session)
+	// Store directly to bypass UpdateSession's LastAccessTime update
+	store.put(session)
 
 	// Try to retrieve the expired session
 	retrieved, err := store.GetSession("U1234567890abcdef")
@@ -209,8 +211,8 @@ func TestExpiredSessionIsTreatedAsNew(t *testing.T) {
 		domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Old response"},
 	)
 
-	// Store directly in the sync.Map to bypass UpdateSession's LastAccessTime update
-	store.sessions.Store("U1234567890abcdef", session)
+	// Store directly to bypass UpdateSession's LastAccessTime update
+	store.put(session)
 
 	// Try to retrieve the expired session
 	retrieved, err := store.GetSession("U1234567890abcdef")
@@ -224,7 +226,7 @@ func TestExpiredSessionIsTreatedAsNew(t *testing.T) {
 	}
 
 	// Verify the session was deleted (lazy cleanup)
-	_, exists := store.sessions.Load("U1234567890abcdef")
+	_, exists := store.sessions["U1234567890abcdef"]
 	if exists {
 		t.Error("expected expired session to be deleted from store")
 	}
@@ -273,3 +275,162 @@ func TestDeleteSessionIsIdempotent(t *testing.T) {
 		t.Errorf("expected no error when deleting non-existent session, got %v", err)
 	}
 }
+
+// TestMemorySessionStoreBehavior runs the shared SessionStore behavioral tests
+// that every session store driver must pass.
+func TestMemorySessionStoreBehavior(t *testing.T) {
+	sessionstoretest.Run(t, func(t *testing.T, timeout time.Duration, maxTurns int) output.SessionStore {
+		return NewMemorySessionStore(timeout, maxTurns)
+	})
+}
+
+// TestUpdateSessionEvictsLeastRecentlyUsedAtCap tests that the least recently used session
+// is evicted once the session cap is reached
+func TestUpdateSessionEvictsLeastRecentlyUsedAtCap(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns, WithMaxSessions(2))
+
+	_ = store.UpdateSession(domain.NewConversationSession("user-a", testTimeout, testMaxTurns))
+	_ = store.UpdateSession(domain.NewConversationSession("user-b", testTimeout, testMaxTurns))
+
+	// Reading user-a makes user-b the least recently used session
+	if session, _ := store.GetSession("user-a"); session == nil {
+		t.Fatal("expected user-a session to exist")
+	}
+
+	_ = store.UpdateSession(domain.NewConversationSession("user-c", testTimeout, testMaxTurns))
+
+	if session, _ := store.GetSession("user-b"); session != nil {
+		t.Error("expected least recently used session user-b to be evicted")
+	}
+	for _, userID := range []string{"user-a", "user-c"} {
+		if session, _ := store.GetSession(userID); session == nil {
+			t.Errorf("expected %s session to be kept", userID)
+		}
+	}
+
+	stats := store.Stats()
+	if stats.Sessions != 2 {
+		t.Errorf("expected 2 sessions, got %d", stats.Sessions)
+	}
+	if stats.CapacityEvictions != 1 {
+		t.Errorf("expected 1 capacity eviction, got %d", stats.CapacityEvictions)
+	}
+}
+
+// TestUpdateSessionWithoutCapKeepsAllSessions tests that sessions are not evicted when no cap is configured
+func TestUpdateSessionWithoutCapKeepsAllSessions(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns)
+
+	for i := 0; i < 100; i++ {
+		_ = store.UpdateSession(domain.NewConversationSession(string(rune('A'+i)), testTimeout, testMaxTurns))
+	}
+
+	stats := store.Stats()
+	if stats.Sessions != 100 || stats.CapacityEvictions != 0 {
+		t.Errorf("expected 100 sessions and no evictions, got %+v", stats)
+	}
+}
+
+// TestSweepExpiredRemovesOnlyExpiredSessions tests that a sweep removes expired sessions
+// of users who never return and counts them as expired evictions
+func TestSweepExpiredRemovesOnlyExpiredSessions(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns)
+
+	expired := domain.NewConversationSession("user-expired", testTimeout, testMaxTurns)
+	expired.LastAccessTime = time.Now().Add(-31 * time.Minute)
+	store.put(expired)
+	_ = store.UpdateSession(domain.NewConversationSession("user-active", testTimeout, testMaxTurns))
+
+	removed := store.sweepExpired()
+
+	if removed != 1 {
+		t.Errorf("expected 1 session removed, got %d", removed)
+	}
+	if _, exists := store.sessions["user-expired"]; exists {
+		t.Error("expected expired session to be removed")
+	}
+	if _, exists := store.sessions["user-active"]; !exists {
+		t.Error("expected active session to be kept")
+	}
+	if stats := store.Stats(); stats.ExpiredEvictions != 1 {
+		t.Errorf("expected 1 expired eviction, got %d", stats.ExpiredEvictions)
+	}
+}
+
+// TestCountSessionsSkipsExpiredSessions tests that expired sessions awaiting cleanup are not counted
+func TestCountSessionsSkipsExpiredSessions(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns)
+
+	expired := domain.NewConversationSession("user-expired", testTimeout, testMaxTurns)
+	expired.LastAccessTime = time.Now().Add(-31 * time.Minute)
+	store.put(expired)
+	_ = store.UpdateSession(domain.NewConversationSession("user-active", testTimeout, testMaxTurns))
+
+	count, err := store.CountSessions()
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 active session, got %d", count)
+	}
+}
+
+// TestJanitorRemovesExpiredSessionsInBackground tests that the janitor sweeps
+// expired sessions without any GetSession call and stops cleanly
+func TestJanitorRemovesExpiredSessionsInBackground(t *testing.T) {
+	store := NewMemorySessionStore(20*time.Millisecond, testMaxTurns)
+	_ = store.UpdateSession(domain.NewConversationSession("user-gone", 20*time.Millisecond, testMaxTurns))
+
+	store.StartJanitor(10 * time.Millisecond)
+	// Starting twice is a no-op
+	store.StartJanitor(10 * time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for store.Stats().Sessions != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	store.StopJanitor()
+	// Stopping twice is a no-op
+	store.StopJanitor()
+
+	stats := store.Stats()
+	if stats.Sessions != 0 {
+		t.Errorf("expected janitor to remove the expired session, got %d sessions", stats.Sessions)
+	}
+	if stats.ExpiredEvictions != 1 {
+		t.Errorf("expected 1 expired eviction, got %d", stats.ExpiredEvictions)
+	}
+}
+
+// TestUpdateSessionStoresCopy tests that the caller's session is not shared with the store,
+// so later writes to it cannot race with the janitor reading the stored session
+func TestUpdateSessionStoresCopy(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns)
+	session := domain.NewConversationSession("U1234567890abcdef", testTimeout, testMaxTurns)
+	_ = store.UpdateSession(session)
+
+	session.LastAccessTime = time.Now().Add(-31 * time.Minute)
+
+	if removed := store.sweepExpired(); removed != 0 {
+		t.Errorf("expected the stored session to be unaffected by the caller, got %d removed", removed)
+	}
+}
+
+// TestGetSessionReturnsCopy tests that changing a retrieved session does not change the stored one
+func TestGetSessionReturnsCopy(t *testing.T) {
+	store := NewMemorySessionStore(testTimeout, testMaxTurns)
+	_ = store.UpdateSession(domain.NewConversationSession("U1234567890abcdef", testTimeout, testMaxTurns))
+
+	retrieved, _ := store.GetSession("U1234567890abcdef")
+	retrieved.AddTurn(
+		domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Unsaved"},
+		domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Unsaved"},
+	)
+
+	stored, _ := store.GetSession("U1234567890abcdef")
+	if len(stored.GetHistory()) != 0 {
+		t.Errorf("expected changes to stay unsaved until UpdateSession, got %d messages", len(stored.GetHistory()))
+	}
+}
@@ -0,0 +1,539 @@
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/internal/adapters/output/circuitbreaker"
+	"golang-template/internal/adapters/output/llmhttp"
+	"golang-template/internal/adapters/output/retry"
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Compile-time check to ensure OllamaClientAdapter implements LMStudioClient interface
+var _ output.LMStudioClient = (*OllamaClientAdapter)(nil)
+
+// OllamaClientAdapter struct - Output adapter for Ollama's native API
+// Chat goes through /api/chat, which streams newline-delimited JSON rather than SSE,
+// and models are listed from /api/tags.
+type OllamaClientAdapter struct {
+	httpClient *http.Client
+	baseURL    string
+	timeout    time.Duration
+
+	// Retries transient failures, and fails requests fast while the server is down
+	client *llmhttp.Client
+
+	// Model caching
+	models *llmhttp.ModelCache
+}
+
+// NewOllamaClientAdapter func - Creates new Ollama client adapter
+func NewOllamaClientAdapter(config configs.LMStudio) (*OllamaClientAdapter, error) {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+
+	// Remove trailing slash if present
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if config.Timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	httpClient := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+
+	breaker := circuitbreaker.NewCircuitBreaker("Ollama at "+baseURL, config.CircuitBreakerThreshold, time.Duration(config.CircuitBreakerCooldown)*time.Second)
+	adapter := &OllamaClientAdapter{
+		httpClient: httpClient,
+		baseURL:    baseURL,
+		timeout:    timeout,
+		client:     llmhttp.NewClient("Ollama", retry.NewRetryPolicy(config.Retry), breaker, llmhttp.WithErrorMessage(errorMessage)),
+		models:     llmhttp.NewModelCache(config.Model, "Ollama"),
+	}
+
+	logrus.Infof("Ollama client adapter initialized with base URL: %s, timeout: %v", baseURL, timeout)
+
+	return adapter, nil
+}
+
+// Streaming configuration constants
+const (
+	streamingChannelBufferSize = 100
+)
+
+// CircuitBreakerStatus returns the state of the circuit breaker guarding Ollama requests
+func (a *OllamaClientAdapter) CircuitBreakerStatus() domain.CircuitBreakerStatus {
+	return a.client.CircuitBreakerStatus()
+}
+
+// ListModels queries the /api/tags endpoint to retrieve the models pulled into Ollama
+func (a *OllamaClientAdapter) ListModels(ctx context.Context) ([]domain.ModelInfo, error) {
+	url := fmt.Sprintf("%s/api/tags", a.baseURL)
+
+	resp, err := a.client.Do(ctx, func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return a.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Parse response
+	var tagsResp tagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tagsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse models response: %w", err)
+	}
+
+	// Convert to domain models
+	models := make([]domain.ModelInfo, len(tagsResp.Models))
+	for i, m := range tagsResp.Models {
+		models[i] = domain.ModelInfo{
+			ID:      m.Name,
+			Object:  "model",
+			OwnedBy: "ollama",
+		}
+	}
+
+	logrus.Infof("Listed %d models from Ollama", len(models))
+
+	return models, nil
+}
+
+// getModel returns the model to use for requests, with caching
+func (a *OllamaClientAdapter) getModel(ctx context.Context) (string, error) {
+	return a.models.Get(ctx, a.ListModels)
+}
+
+// buildChatRequest converts a domain request to an /api/chat request body
+func (a *OllamaClientAdapter) buildChatRequest(ctx context.Context, request domain.ChatCompletionRequest, stream bool) ([]byte, string, error) {
+	// Get model to use
+	model, err := a.getModel(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get model: %w", err)
+	}
+
+	// Override model if specified in request
+	if request.Model != nil && *request.Model != "" {
+		model = *request.Model
+	}
+
+	reqBody := chatAPIRequest{
+		Model:    model,
+		Messages: toChatMessagesAPI(request.Messages),
+		Stream:   stream,
+	}
+
+	// Ollama streams tool calls in a single chunk, but the streaming port has no way to return them
+	if !stream {
+		reqBody.Tools = toToolsAPI(request.Tools)
+	}
+
+	if request.Temperature != nil {
+		reqBody.Options = &optionsAPI{Temperature: request.Temperature}
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return bodyBytes, model, nil
+}
+
+// ChatCompletion sends a non-streaming chat request to Ollama
+func (a *OllamaClientAdapter) ChatCompletion(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	bodyBytes, _, err := a.buildChatRequest(ctx, request, false)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/chat", a.baseURL)
+
+	// Execute request with retry
+	resp, err := a.client.Do(ctx, func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return a.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send chat completion request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Parse response
+	var apiResp chatAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse chat completion response: %w", err)
+	}
+	if apiResp.Error != "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrLMStudioUnavailable, apiResp.Error)
+	}
+
+	// Build domain response
+	response := &domain.ChatCompletionResponse{
+		Content:          apiResp.Message.Content,
+		ToolCalls:        fromToolCallsAPI(apiResp.Message.ToolCalls),
+		Model:            apiResp.Model,
+		PromptTokens:     apiResp.PromptEvalCount,
+		CompletionTokens: apiResp.EvalCount,
+		TotalTokens:      apiResp.PromptEvalCount + apiResp.EvalCount,
+	}
+
+	logrus.Infof("Chat completion successful, model: %s, tokens: %d", response.Model, response.TotalTokens)
+
+	return response, nil
+}
+
+// ChatCompletionStream sends a streaming chat request to Ollama
+// Returns a read-only channel that emits ChatCompletionChunk as they arrive
+func (a *OllamaClientAdapter) ChatCompletionStream(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+	bodyBytes, model, err := a.buildChatRequest(ctx, request, true)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/chat", a.baseURL)
+
+	// Create request (no retry for streaming - we want immediate feedback)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create streaming request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	// Send request
+	resp, err := a.client.DoOnce(func() (*http.Response, error) {
+		return a.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create buffered channel for chunks
+	chunkChan := make(chan domain.ChatCompletionChunk, streamingChannelBufferSize)
+
+	// Launch goroutine to parse NDJSON and emit chunks
+	go a.processStreamingResponse(ctx, resp, chunkChan)
+
+	logrus.Infof("Started streaming chat completion with model: %s", model)
+
+	return chunkChan, nil
+}
+
+// processStreamingResponse parses NDJSON from response body and sends chunks to channel
+// Every line is a complete JSON object; the last one has done set. This runs in a goroutine
+// and is responsible for closing the channel when done.
+func (a *OllamaClientAdapter) processStreamingResponse(ctx context.Context, resp *http.Response, chunkChan chan<- domain.ChatCompletionChunk) {
+	// Ensure cleanup happens
+	defer func() {
+		resp.Body.Close()
+		close(chunkChan)
+		logrus.Debug("Streaming response processing completed, channel closed")
+	}()
+
+	scanner := bufio.NewScanner(resp.Body)
+
+	for {
+		// Check context cancellation before reading
+		select {
+		case <-ctx.Done():
+			logrus.Debug("Streaming cancelled by context")
+			chunkChan <- domain.ChatCompletionChunk{
+				Done:  true,
+				Error: fmt.Errorf("streaming cancelled: %w", ctx.Err()),
+			}
+			return
+		default:
+		}
+
+		// Read next line
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				logrus.Errorf("Error reading streaming response: %v", err)
+				chunkChan <- domain.ChatCompletionChunk{
+					Done:  true,
+					Error: fmt.Errorf("failed to read streaming response: %w", err),
+				}
+			} else {
+				// EOF reached without done - treat as normal completion
+				logrus.Debug("Streaming EOF reached")
+				chunkChan <- domain.ChatCompletionChunk{Done: true}
+			}
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines
+		if line == "" {
+			continue
+		}
+
+		var streamResp chatAPIResponse
+		if err := json.Unmarshal([]byte(line), &streamResp); err != nil {
+			logrus.Warnf("Error parsing NDJSON line: %v, line: %s", err, line)
+			continue // Skip malformed lines but continue processing
+		}
+
+		// Ollama reports failures during generation as an error object
+		if streamResp.Error != "" {
+			chunkChan <- domain.ChatCompletionChunk{
+				Done:  true,
+				Error: fmt.Errorf("%w: %s", domain.ErrLMStudioUnavailable, streamResp.Error),
+			}
+			return
+		}
+
+		if streamResp.Message.Content != "" {
+			chunkChan <- domain.ChatCompletionChunk{Content: streamResp.Message.Content}
+		}
+
+		if streamResp.Done {
+			logrus.Debugf("Received done line, reason: %s", streamResp.DoneReason)
+			chunkChan <- domain.ChatCompletionChunk{Done: true}
+			return
+		}
+	}
+}
+
+// errorMessage returns the message of an Ollama {"error": "..."} body, or the body itself
+func errorMessage(body []byte) string {
+	var errResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		return errResp.Error
+	}
+	return string(body)
+}
+
+// API request/response structures for Ollama's native API
+
+// chatMessageAPI represents a message in the /api/chat request
+// Images are raw base64 strings without the data URL prefix.
+type chatMessageAPI struct {
+	Role      string        `json:"role"`
+	Content   string        `json:"content"`
+	Images    []string      `json:"images,omitempty"`
+	ToolCalls []toolCallAPI `json:"tool_calls,omitempty"`
+	ToolName  string        `json:"tool_name,omitempty"`
+}
+
+// toolAPI represents a function tool offered to the model
+type toolAPI struct {
+	Type     string          `json:"type"`
+	Function toolFunctionAPI `json:"function"`
+}
+
+// toolFunctionAPI represents the definition of a function tool
+type toolFunctionAPI struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// toolCallAPI represents a function call made by the model
+// Unlike the OpenAI API, calls have no ID and arguments are a JSON object rather than a string.
+type toolCallAPI struct {
+	Function toolCallFunctionAPI `json:"function"`
+}
+
+// toolCallFunctionAPI represents the function name and arguments of a tool call
+type toolCallFunctionAPI struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// optionsAPI represents the model parameters of a request
+type optionsAPI struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+}
+
+// toChatMessagesAPI converts domain chat messages to API messages
+// Text parts are joined into the content and base64 data URL images become images.
+// Tool results are matched to the name of the call they answer, since Ollama has no call IDs.
+func toChatMessagesAPI(messages []domain.ChatMessage) []chatMessageAPI {
+	apiMessages := make([]chatMessageAPI, len(messages))
+	toolNames := make(map[string]string)
+
+	for i, msg := range messages {
+		apiMessages[i] = chatMessageAPI{
+			Role:      string(msg.Role),
+			Content:   msg.Content,
+			ToolCalls: toToolCallsAPI(msg.ToolCalls),
+		}
+
+		for _, call := range msg.ToolCalls {
+			toolNames[call.ID] = call.Name
+		}
+		if msg.ToolCallID != "" {
+			apiMessages[i].ToolName = toolNames[msg.ToolCallID]
+		}
+
+		if len(msg.Parts) == 0 {
+			continue
+		}
+
+		var texts []string
+		for _, part := range msg.Parts {
+			switch part.Type {
+			case domain.ChatContentPartTypeText:
+				texts = append(texts, part.Text)
+			case domain.ChatContentPartTypeImageURL:
+				image, ok := base64Image(part.ImageURL)
+				if !ok {
+					logrus.Warnf("Skipping image that is not a base64 data URL")
+					continue
+				}
+				apiMessages[i].Images = append(apiMessages[i].Images, image)
+			default:
+				logrus.Warnf("Skipping unsupported chat content part type: %s", part.Type)
+			}
+		}
+		apiMessages[i].Content = strings.Join(texts, "\n")
+	}
+
+	return apiMessages
+}
+
+// base64Image returns the base64 data of a "data:<type>;base64,<data>" URL
+func base64Image(dataURL string) (string, bool) {
+	if !strings.HasPrefix(dataURL, "data:") {
+		return "", false
+	}
+	_, data, found := strings.Cut(dataURL, ";base64,")
+	return data, found
+}
+
+// toToolsAPI converts domain tool definitions to API function tools
+func toToolsAPI(tools []domain.ChatToolDefinition) []toolAPI {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	apiTools := make([]toolAPI, len(tools))
+	for i, tool := range tools {
+		apiTools[i] = toolAPI{
+			Type: "function",
+			Function: toolFunctionAPI{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		}
+	}
+	return apiTools
+}
+
+// toToolCallsAPI converts domain tool calls to API tool calls
+// Arguments that are not valid JSON are sent as an empty object.
+func toToolCallsAPI(calls []domain.ChatToolCall) []toolCallAPI {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	apiCalls := make([]toolCallAPI, len(calls))
+	for i, call := range calls {
+		arguments := json.RawMessage(call.Arguments)
+		if !json.Valid(arguments) {
+			arguments = json.RawMessage("{}")
+		}
+		apiCalls[i] = toolCallAPI{
+			Function: toolCallFunctionAPI{
+				Name:      call.Name,
+				Arguments: arguments,
+			},
+		}
+	}
+	return apiCalls
+}
+
+// fromToolCallsAPI converts API tool calls to domain tool calls
+// Ollama gives calls no IDs, so they are numbered in the order they were made.
+func fromToolCallsAPI(calls []toolCallAPI) []domain.ChatToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	domainCalls := make([]domain.ChatToolCall, len(calls))
+	for i, call := range calls {
+		arguments := string(call.Function.Arguments)
+		if arguments == "" || arguments == "null" {
+			arguments = "{}"
+		}
+		domainCalls[i] = domain.ChatToolCall{
+			ID:        fmt.Sprintf("call_%d", i),
+			Name:      call.Function.Name,
+			Arguments: arguments,
+		}
+	}
+	return domainCalls
+}
+
+// chatAPIRequest represents the request body for /api/chat
+type chatAPIRequest struct {
+	Model    string           `json:"model"`
+	Messages []chatMessageAPI `json:"messages"`
+	Stream   bool             `json:"stream"`
+	Tools    []toolAPI        `json:"tools,omitempty"`
+	Options  *optionsAPI      `json:"options,omitempty"`
+}
+
+// chatAPIResponse represents a non-streaming /api/chat response or one line of a streaming one
+type chatAPIResponse struct {
+	Model     string `json:"model"`
+	CreatedAt string `json:"created_at"`
+	Message   struct {
+		Role      string        `json:"role"`
+		Content   string        `json:"content"`
+		ToolCalls []toolCallAPI `json:"tool_calls,omitempty"`
+	} `json:"message"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason,omitempty"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	Error           string `json:"error,omitempty"`
+}
+
+// tagsResponse represents the response from the /api/tags endpoint
+type tagsResponse struct {
+	Models []struct {
+		Name       string `json:"name"`
+		Model      string `json:"model"`
+		ModifiedAt string `json:"modified_at"`
+		Size       int64  `json:"size"`
+	} `json:"models"`
+}
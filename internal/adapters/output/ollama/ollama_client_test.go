@@ -0,0 +1,288 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/internal/domain"
+)
+
+// newTestAdapter creates an adapter for the mock server with a configured model
+func newTestAdapter(t *testing.T, server *httptest.Server) *OllamaClientAdapter {
+	t.Helper()
+	adapter, err := NewOllamaClientAdapter(configs.LMStudio{BaseURL: server.URL, Model: "llama3.2", Timeout: 30})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+	return adapter
+}
+
+// TestNewOllamaClientAdapterWithDefaultValues tests adapter construction with default values
+func TestNewOllamaClientAdapterWithDefaultValues(t *testing.T) {
+	adapter, err := NewOllamaClientAdapter(configs.LMStudio{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if adapter.baseURL != "http://localhost:11434" {
+		t.Errorf("expected default baseURL to be http://localhost:11434, got: %s", adapter.baseURL)
+	}
+
+	if adapter.timeout != 60*time.Second {
+		t.Errorf("expected default timeout to be 60s, got: %v", adapter.timeout)
+	}
+}
+
+// TestChatCompletionSuccess tests non-streaming chat with mock HTTP server
+func TestChatCompletionSuccess(t *testing.T) {
+	var reqBody map[string]json.RawMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" || r.Method != http.MethodPost {
+			t.Errorf("expected POST /api/chat, got: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model":"llama3.2","created_at":"2024-01-01T00:00:00Z","message":{"role":"assistant","content":"Hi there!"},"done":true,"done_reason":"stop","prompt_eval_count":12,"eval_count":4}`)
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+	temperature := 0.2
+
+	response, err := adapter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{
+		Messages:    []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Hello"}},
+		Temperature: &temperature,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if string(reqBody["stream"]) != "false" || string(reqBody["model"]) != `"llama3.2"` {
+		t.Errorf("expected stream=false with the configured model, got stream=%s model=%s", reqBody["stream"], reqBody["model"])
+	}
+	if string(reqBody["options"]) != `{"temperature":0.2}` {
+		t.Errorf("expected temperature sent as an option, got: %s", reqBody["options"])
+	}
+	if response.Content != "Hi there!" {
+		t.Errorf("expected content 'Hi there!', got: %s", response.Content)
+	}
+	if response.PromptTokens != 12 || response.CompletionTokens != 4 || response.TotalTokens != 16 {
+		t.Errorf("expected tokens 12+4=16, got: %+v", response)
+	}
+}
+
+// TestChatCompletionToolCallsAndImages tests tool call mapping and base64 images in both directions
+func TestChatCompletionToolCallsAndImages(t *testing.T) {
+	var reqBody chatAPIRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model":"llama3.2","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"add_todo","arguments":{"title":"Buy milk"}}}]},"done":true}`)
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+
+	response, err := adapter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{
+			{Role: domain.ChatMessageRoleUser, Parts: []domain.ChatContentPart{
+				{Type: domain.ChatContentPartTypeText, Text: "What is this?"},
+				{Type: domain.ChatContentPartTypeImageURL, ImageURL: "data:image/jpeg;base64,aGVsbG8="},
+			}},
+			{Role: domain.ChatMessageRoleAssistant, ToolCalls: []domain.ChatToolCall{{ID: "call_0", Name: "list_todos", Arguments: `{}`}}},
+			{Role: domain.ChatMessageRoleTool, ToolCallID: "call_0", Content: "No todos"},
+		},
+		Tools: []domain.ChatToolDefinition{{Name: "add_todo", Description: "Add a todo", Parameters: json.RawMessage(`{"type":"object"}`)}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Assert request
+	if len(reqBody.Messages) != 3 {
+		t.Fatalf("expected 3 messages, got: %d", len(reqBody.Messages))
+	}
+	if reqBody.Messages[0].Content != "What is this?" || len(reqBody.Messages[0].Images) != 1 || reqBody.Messages[0].Images[0] != "aGVsbG8=" {
+		t.Errorf("expected text content and raw base64 image, got: %+v", reqBody.Messages[0])
+	}
+	if string(reqBody.Messages[1].ToolCalls[0].Function.Arguments) != `{}` {
+		t.Errorf("expected arguments sent as a JSON object, got: %s", reqBody.Messages[1].ToolCalls[0].Function.Arguments)
+	}
+	if reqBody.Messages[2].ToolName != "list_todos" {
+		t.Errorf("expected tool result named after its call, got: %q", reqBody.Messages[2].ToolName)
+	}
+	if len(reqBody.Tools) != 1 || reqBody.Tools[0].Function.Name != "add_todo" {
+		t.Errorf("expected add_todo tool offered, got: %+v", reqBody.Tools)
+	}
+
+	// Assert response
+	if len(response.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got: %d", len(response.ToolCalls))
+	}
+	call := response.ToolCalls[0]
+	if call.ID != "call_0" || call.Name != "add_todo" || call.Arguments != `{"title":"Buy milk"}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+}
+
+// TestChatCompletionStreamNDJSON tests that streamed NDJSON lines become chunks
+func TestChatCompletionStreamNDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody chatAPIRequest
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		if !reqBody.Stream {
+			t.Error("expected stream=true for streaming")
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher := w.(http.Flusher)
+		for _, content := range []string{"Hello", ", ", "World", "!"} {
+			fmt.Fprintf(w, `{"model":"llama3.2","message":{"role":"assistant","content":%q},"done":false}`+"\n", content)
+			flusher.Flush()
+		}
+		fmt.Fprint(w, `{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop"}`+"\n")
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+
+	chunkChan, err := adapter.ChatCompletionStream(context.Background(), domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Say hello world"}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var receivedContent strings.Builder
+	var finalChunk domain.ChatCompletionChunk
+	for chunk := range chunkChan {
+		if chunk.Error != nil {
+			t.Errorf("unexpected error in chunk: %v", chunk.Error)
+		}
+		if chunk.Done {
+			finalChunk = chunk
+		}
+		receivedContent.WriteString(chunk.Content)
+	}
+
+	if !finalChunk.Done {
+		t.Error("expected final chunk to have Done=true")
+	}
+	if receivedContent.String() != "Hello, World!" {
+		t.Errorf("expected content 'Hello, World!', got: '%s'", receivedContent.String())
+	}
+}
+
+// TestChatCompletionStreamErrorLine tests that an error object in the stream ends it with an error chunk
+func TestChatCompletionStreamErrorLine(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"model":"llama3.2","message":{"role":"assistant","content":"Hel"},"done":false}`+"\n")
+		fmt.Fprint(w, `{"error":"model runner has unexpectedly stopped"}`+"\n")
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+
+	chunkChan, err := adapter.ChatCompletionStream(context.Background(), domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	var lastChunk domain.ChatCompletionChunk
+	for chunk := range chunkChan {
+		lastChunk = chunk
+	}
+
+	if !lastChunk.Done || !errors.Is(lastChunk.Error, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected a final chunk with ErrLMStudioUnavailable, got: %+v", lastChunk)
+	}
+}
+
+// TestListModelsFromTags tests that /api/tags models are listed by name, retrying 5xx errors
+func TestListModelsFromTags(t *testing.T) {
+	var requestCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("expected path /api/tags, got: %s", r.URL.Path)
+		}
+		if atomic.AddInt32(&requestCount, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"models":[{"name":"llama3.2:latest","model":"llama3.2:latest","size":2019393189},{"name":"qwen2.5:7b","model":"qwen2.5:7b"}]}`)
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+
+	models, err := adapter.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error after retry, got: %v", err)
+	}
+
+	if len(models) != 2 || models[0].ID != "llama3.2:latest" || models[1].ID != "qwen2.5:7b" {
+		t.Errorf("unexpected models: %+v", models)
+	}
+	if atomic.LoadInt32(&requestCount) != 2 {
+		t.Errorf("expected 2 requests (1 failure + 1 success), got: %d", requestCount)
+	}
+}
+
+// TestErrorMapping tests that unknown models map to ErrInvalidRequest and unreachable servers to ErrLMStudioUnavailable
+func TestErrorMapping(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"model \"gpt-4\" not found, try pulling it first"}`)
+	}))
+	defer server.Close()
+
+	adapter := newTestAdapter(t, server)
+	request := domain.ChatCompletionRequest{Messages: []domain.ChatMessage{{Role: domain.ChatMessageRoleUser, Content: "Hello"}}}
+
+	_, err := adapter.ChatCompletion(context.Background(), request)
+	if !errors.Is(err, domain.ErrInvalidRequest) || !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("expected ErrInvalidRequest with Ollama's message, got: %v", err)
+	}
+	if atomic.LoadInt32(&requestCount) != 1 {
+		t.Errorf("expected exactly 1 request (no retry for 4xx), got: %d", requestCount)
+	}
+
+	_, err = adapter.ChatCompletionStream(context.Background(), request)
+	if !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for streaming, got: %v", err)
+	}
+
+	// Nothing listens on the closed server's port
+	server.Close()
+	_, err = adapter.ChatCompletionStream(context.Background(), request)
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable for an unreachable server, got: %v", err)
+	}
+}
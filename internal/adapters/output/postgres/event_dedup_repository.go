@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Compile-time check to ensure EventDedupRepository implements EventDedupStore interface
+var _ output.EventDedupStore = (*EventDedupRepository)(nil)
+
+// processedWebhookEvent struct - Persistence model for processed LINE webhook event IDs
+type processedWebhookEvent struct {
+	EventID   string    `gorm:"type:varchar(64);primary_key;"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;index;"`
+}
+
+// TableName func
+func (processedWebhookEvent) TableName() string {
+	return "processed_webhook_events"
+}
+
+// EventDedupRepository struct - Secondary/Driven adapter for webhook event deduplication in PostgreSQL
+// Expired rows are purged lazily, at most once per TTL.
+type EventDedupRepository struct {
+	dbGorm    *gorm.DB
+	ttl       time.Duration
+	purgeMu   sync.Mutex
+	lastPurge time.Time
+}
+
+// NewEventDedupRepository func - Creates new PostgreSQL dedup repository
+func NewEventDedupRepository(dbGorm *gorm.DB, ttl time.Duration) *EventDedupRepository {
+	logrus.Info("Migrate processed webhook events table ...")
+	if err := dbGorm.AutoMigrate(&processedWebhookEvent{}); err != nil {
+		panic(err)
+	}
+	return &EventDedupRepository{
+		dbGorm:    dbGorm,
+		ttl:       ttl,
+		lastPurge: time.Now(),
+	}
+}
+
+// MarkSeen func - Inserts the event ID, reporting true if a row already existed within the TTL
+func (p *EventDedupRepository) MarkSeen(eventID string) (bool, error) {
+	now := time.Now()
+	p.purgeExpired(now)
+
+	record := processedWebhookEvent{
+		EventID:   eventID,
+		CreatedAt: now,
+	}
+	result := p.dbGorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		logrus.Errorln(result.Error)
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	// Row exists - treat it as new if it has outlived the TTL but was not purged yet
+	expired := p.dbGorm.Model(&processedWebhookEvent{}).
+		Where("event_id = ? AND created_at < ?", eventID, now.Add(-p.ttl)).
+		Update("created_at", now)
+	if expired.Error != nil {
+		logrus.Errorln(expired.Error)
+		return false, expired.Error
+	}
+
+	return expired.RowsAffected == 0, nil
+}
+
+// purgeExpired deletes rows older than the TTL, at most once per TTL
+func (p *EventDedupRepository) purgeExpired(now time.Time) {
+	p.purgeMu.Lock()
+	if now.Sub(p.lastPurge) < p.ttl {
+		p.purgeMu.Unlock()
+		return
+	}
+	p.lastPurge = now
+	p.purgeMu.Unlock()
+
+	if err := p.dbGorm.Where("created_at < ?", now.Add(-p.ttl)).Delete(&processedWebhookEvent{}).Error; err != nil {
+		logrus.Errorln(err)
+	}
+}
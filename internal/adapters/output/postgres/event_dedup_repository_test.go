@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestPostgresEventDedupRepository runs the EventDedupStore tests against PostgreSQL.
+// Requires POSTGRES_TEST_DSN (e.g. "host=localhost user=postgres password=secret dbname=postgres sslmode=disable").
+func TestPostgresEventDedupRepository(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set, skipping PostgreSQL event dedup tests")
+	}
+
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Error),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	const ttl = time.Hour
+
+	// newRepository creates a repository and an event ID prefix unique to the test, cleaned up afterwards
+	newRepository := func(t *testing.T) (*EventDedupRepository, string) {
+		repo := NewEventDedupRepository(db, ttl)
+		prefix := fmt.Sprintf("E-%d-", time.Now().UnixNano())
+		t.Cleanup(func() {
+			db.Where("event_id LIKE ?", prefix+"%").Delete(&processedWebhookEvent{})
+		})
+		return repo, prefix
+	}
+
+	// insertAged stores an event ID as if it had been seen age ago
+	insertAged := func(t *testing.T, eventID string, age time.Duration) {
+		t.Helper()
+		if err := db.Create(&processedWebhookEvent{EventID: eventID, CreatedAt: time.Now().Add(-age)}).Error; err != nil {
+			t.Fatalf("failed to insert event: %v", err)
+		}
+	}
+
+	// countEvents returns how many rows exist for an event ID
+	countEvents := func(t *testing.T, eventID string) int64 {
+		t.Helper()
+		var count int64
+		if err := db.Model(&processedWebhookEvent{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count events: %v", err)
+		}
+		return count
+	}
+
+	t.Run("FirstSeenAndRepeat", func(t *testing.T) {
+		repo, prefix := newRepository(t)
+
+		first, err := repo.MarkSeen(prefix + "event")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		repeat, err := repo.MarkSeen(prefix + "event")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		other, _ := repo.MarkSeen(prefix + "other")
+
+		if first {
+			t.Error("expected first MarkSeen to report unseen")
+		}
+		if !repeat {
+			t.Error("expected repeated MarkSeen to report seen")
+		}
+		if other {
+			t.Error("expected a different event ID to report unseen")
+		}
+	})
+
+	t.Run("ExpiredRowCountsAsNew", func(t *testing.T) {
+		// Arrange
+		repo, prefix := newRepository(t)
+		insertAged(t, prefix+"event", 2*ttl)
+
+		// Act
+		expired, err := repo.MarkSeen(prefix + "event")
+		repeat, _ := repo.MarkSeen(prefix + "event")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if expired {
+			t.Error("expected an event older than the TTL to report unseen")
+		}
+		if !repeat {
+			t.Error("expected the refreshed event to report seen")
+		}
+	})
+
+	t.Run("PurgeDeletesOldRows", func(t *testing.T) {
+		// Arrange
+		repo, prefix := newRepository(t)
+		insertAged(t, prefix+"old", 2*ttl)
+		insertAged(t, prefix+"recent", time.Minute)
+		repo.lastPurge = time.Now().Add(-2 * ttl)
+
+		// Act
+		_, err := repo.MarkSeen(prefix + "trigger")
+		insertAged(t, prefix+"old-after-purge", 2*ttl)
+		_, _ = repo.MarkSeen(prefix + "trigger-again")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if countEvents(t, prefix+"old") != 0 {
+			t.Error("expected the purge to delete the row older than the TTL")
+		}
+		if countEvents(t, prefix+"recent") != 1 {
+			t.Error("expected the purge to keep the row within the TTL")
+		}
+		if countEvents(t, prefix+"old-after-purge") != 1 {
+			t.Error("expected no second purge within the TTL")
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Compile-time checks to ensure PostgresSessionStore implements SessionStore and SessionCounter interfaces
+var (
+	_ output.SessionStore   = (*PostgresSessionStore)(nil)
+	_ output.SessionCounter = (*PostgresSessionStore)(nil)
+)
+
+// conversationSessionRecord struct - Persistence model for a conversation session
+// Messages are stored as a JSON array of domain.ChatMessage. UserID holds the conversation key,
+// which is a LINE user, group, or room ID, so it is wider than a bare LINE ID.
+type conversationSessionRecord struct {
+	UserID         string    `gorm:"type:varchar(255);primary_key;"`
+	Messages       string    `gorm:"type:text;not null;"`
+	LastAccessTime time.Time `gorm:"type:timestamp;not null;index;"`
+	Persona        string    `gorm:"type:varchar(64);not null;default:'';"`
+}
+
+// TableName func
+func (conversationSessionRecord) TableName() string {
+	return "conversation_sessions"
+}
+
+// PostgresSessionStore struct - Secondary/Driven adapter for session storage in PostgreSQL
+// Sessions survive restarts and are shared by every replica using the same database.
+// Stores session configuration for timeout and maxTurns to rebuild sessions read from the database.
+type PostgresSessionStore struct {
+	dbGorm   *gorm.DB
+	timeout  time.Duration
+	maxTurns int
+}
+
+// NewPostgresSessionStore func - Creates new PostgreSQL session store with configurable session parameters.
+// timeout: Duration after which sessions expire
+// maxTurns: Maximum number of conversation turns to retain in session history
+func NewPostgresSessionStore(dbGorm *gorm.DB, timeout time.Duration, maxTurns int) *PostgresSessionStore {
+	logrus.Info("Migrate conversation sessions table ...")
+	if err := dbGorm.AutoMigrate(&conversationSessionRecord{}); err != nil {
+		panic(err)
+	}
+	return &PostgresSessionStore{
+		dbGorm:   dbGorm,
+		timeout:  timeout,
+		maxTurns: maxTurns,
+	}
+}
+
+// GetTimeout returns the configured session timeout duration.
+func (p *PostgresSessionStore) GetTimeout() time.Duration {
+	return p.timeout
+}
+
+// GetMaxTurns returns the configured maximum conversation turns.
+func (p *PostgresSessionStore) GetMaxTurns() int {
+	return p.maxTurns
+}
+
+// GetSession retrieves a conversation session by LINE user ID.
+// Returns nil if the session does not exist or has expired. Expired sessions are deleted (lazy cleanup).
+// LastAccessTime is updated for valid sessions.
+func (p *PostgresSessionStore) GetSession(userID string) (*domain.ConversationSession, error) {
+	var record conversationSessionRecord
+	if err := p.dbGorm.Where("user_id = ?", userID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		logrus.Errorln(err)
+		return nil, err
+	}
+
+	session := domain.NewConversationSession(record.UserID, p.timeout, p.maxTurns)
+	session.LastAccessTime = record.LastAccessTime
+	session.Persona = record.Persona
+
+	// Check if session is expired
+	if session.IsExpired() {
+		// Lazy cleanup: delete expired session
+		if err := p.DeleteSession(userID); err != nil {
+			logrus.Warnf("Failed to delete expired session for user %s: %v", userID, err)
+		}
+		return nil, nil
+	}
+
+	if err := json.Unmarshal([]byte(record.Messages), &session.Messages); err != nil {
+		// If data is malformed, delete and return nil
+		logrus.Warnf("Discarding malformed session for user %s: %v", userID, err)
+		_ = p.DeleteSession(userID)
+		return nil, nil
+	}
+
+	// Update LastAccessTime for valid session
+	session.LastAccessTime = time.Now()
+	if err := p.dbGorm.Model(&conversationSessionRecord{}).
+		Where("user_id = ?", userID).
+		Update("last_access_time", session.LastAccessTime).Error; err != nil {
+		logrus.Errorln(err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// UpdateSession creates or updates a conversation session.
+// The session's LastAccessTime is updated to the current time before storing.
+func (p *PostgresSessionStore) UpdateSession(session *domain.ConversationSession) error {
+	session.LastAccessTime = time.Now()
+
+	messages, err := json.Marshal(session.Messages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session messages: %w", err)
+	}
+
+	record := conversationSessionRecord{
+		UserID:         session.UserID,
+		Messages:       string(messages),
+		LastAccessTime: session.LastAccessTime,
+		Persona:        session.Persona,
+	}
+
+	err = p.dbGorm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"messages", "last_access_time", "persona"}),
+	}).Create(&record).Error
+	if err != nil {
+		logrus.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSession removes a conversation session by LINE user ID.
+// This operation is idempotent - deleting a non-existent session does not return an error.
+func (p *PostgresSessionStore) DeleteSession(userID string) error {
+	if err := p.dbGorm.Where("user_id = ?", userID).Delete(&conversationSessionRecord{}).Error; err != nil {
+		logrus.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+// CountSessions returns the number of sessions that have not expired.
+// Sessions are expired when their last access is older than the session timeout.
+func (p *PostgresSessionStore) CountSessions() (int, error) {
+	var count int64
+	if err := p.dbGorm.Model(&conversationSessionRecord{}).
+		Where("last_access_time > ?", time.Now().Add(-p.timeout)).
+		Count(&count).Error; err != nil {
+		logrus.Errorln(err)
+		return 0, err
+	}
+	return int(count), nil
+}
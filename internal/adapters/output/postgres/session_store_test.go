@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"golang-template/internal/adapters/output/sessionstoretest"
+	"golang-template/internal/ports/output"
+
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestPostgresSessionStoreBehavior runs the shared SessionStore behavioral tests against PostgreSQL.
+// Requires POSTGRES_TEST_DSN (e.g. "host=localhost user=postgres password=secret dbname=postgres sslmode=disable").
+func TestPostgresSessionStoreBehavior(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set, skipping PostgreSQL session store tests")
+	}
+
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Error),
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	sessionstoretest.Run(t, func(t *testing.T, timeout time.Duration, maxTurns int) output.SessionStore {
+		store := NewPostgresSessionStore(db, timeout, maxTurns)
+		t.Cleanup(func() {
+			db.Where("user_id LIKE ?", "U-"+t.Name()+"%").Delete(&conversationSessionRecord{})
+		})
+		return store
+	})
+}
@@ -44,6 +44,7 @@ func (p *TodoRepository) CreateTodo(request domain.TodoRequest) (*domain.TodoRes
 		Description: request.Description,
 		Image:       &encodingImage,
 		Status:      (*domain.TodoStatus)(request.Status),
+		OwnerID:     request.OwnerID,
 	}
 	if request.Date != nil {
 		_date, err := time.Parse(layoutDateTimeRFC3339, *request.Date)
@@ -65,6 +66,7 @@ func (p *TodoRepository) CreateTodo(request domain.TodoRequest) (*domain.TodoRes
 		Date:        &df,
 		Image:       todo.Image,
 		Status:      todo.Status,
+		OwnerID:     todo.OwnerID,
 		CreatedAt:   todo.CreatedAt,
 		UpdatedAt:   todo.UpdatedAt,
 		DeletedAt:   todo.DeletedAt,
@@ -81,7 +83,8 @@ func (p *TodoRepository) UpdateTodo(request domain.TodoRequest) (*domain.TodoRes
 		df            string
 	)
 	payload := domain.QueryTodoRequest{
-		ID: request.ID,
+		ID:      request.ID,
+		OwnerID: request.OwnerID,
 	}
 	condition := p.condition(payload)
 	columns := p.updateColumns(request)
@@ -124,6 +127,7 @@ func (p *TodoRepository) UpdateTodo(request domain.TodoRequest) (*domain.TodoRes
 		Date:        &df,
 		Image:       &decodingImage,
 		Status:      todo.Status,
+		OwnerID:     todo.OwnerID,
 		CreatedAt:   todo.CreatedAt,
 		UpdatedAt:   todo.UpdatedAt,
 		DeletedAt:   todo.DeletedAt,
@@ -139,6 +143,9 @@ func (p *TodoRepository) condition(condition domain.QueryTodoRequest) map[string
 	if condition.Status != nil {
 		expression["status"] = *condition.Status
 	}
+	if condition.OwnerID != nil {
+		expression["owner_id"] = *condition.OwnerID
+	}
 	return expression
 }
 
@@ -176,7 +183,8 @@ func (p *TodoRepository) DeleteTodo(request domain.TodoRequest) (*domain.TodoRes
 		df            string
 	)
 	payload := domain.QueryTodoRequest{
-		ID: request.ID,
+		ID:      request.ID,
+		OwnerID: request.OwnerID,
 	}
 	condition := p.condition(payload)
 	if err := p.dbGorm.Table(todo.TableName()).Where(condition).First(&todo).Error; err != nil {
@@ -211,6 +219,7 @@ func (p *TodoRepository) DeleteTodo(request domain.TodoRequest) (*domain.TodoRes
 		Date:        &df,
 		Image:       &decodingImage,
 		Status:      todo.Status,
+		OwnerID:     todo.OwnerID,
 		CreatedAt:   todo.CreatedAt,
 		UpdatedAt:   todo.UpdatedAt,
 		DeletedAt:   todo.DeletedAt,
@@ -299,6 +308,7 @@ func (p *TodoRepository) GetTodo(condition domain.QueryTodoRequest) (*domain.Tod
 			Date:        &df,
 			Image:       &decodingImage,
 			Status:      todo.Status,
+			OwnerID:     todo.OwnerID,
 			CreatedAt:   todo.CreatedAt,
 			UpdatedAt:   todo.UpdatedAt,
 			DeletedAt:   todo.DeletedAt,
@@ -0,0 +1,238 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Compile-time checks to ensure RedisSessionStore implements SessionStore, SessionTurnAppender, and SessionCounter interfaces
+var (
+	_ output.SessionStore        = (*RedisSessionStore)(nil)
+	_ output.SessionTurnAppender = (*RedisSessionStore)(nil)
+	_ output.SessionCounter      = (*RedisSessionStore)(nil)
+)
+
+// sessionKeyPrefix namespaces session keys in a shared Redis database
+const sessionKeyPrefix = "linebot:session:"
+
+// RedisSessionStore struct - Output adapter for session storage in Redis (or any RESP-compatible server)
+// Each session is up to three keys sharing the session timeout as native TTL:
+//   - linebot:session:{userID}           last access time in Unix milliseconds
+//   - linebot:session:{userID}:messages  list of JSON-encoded domain.ChatMessage
+//   - linebot:session:{userID}:persona   name of the active persona, absent for the default
+//
+// Sessions are shared by every replica connected to the same server.
+type RedisSessionStore struct {
+	client   *goredis.Client
+	timeout  time.Duration
+	maxTurns int
+}
+
+// NewRedisSessionStore creates a new Redis session store with configurable session parameters.
+// timeout: Duration after which sessions expire, also used as the key TTL
+// maxTurns: Maximum number of conversation turns to retain in session history
+func NewRedisSessionStore(client *goredis.Client, timeout time.Duration, maxTurns int) *RedisSessionStore {
+	return &RedisSessionStore{
+		client:   client,
+		timeout:  timeout,
+		maxTurns: maxTurns,
+	}
+}
+
+// GetTimeout returns the configured session timeout duration.
+func (r *RedisSessionStore) GetTimeout() time.Duration {
+	return r.timeout
+}
+
+// GetMaxTurns returns the configured maximum conversation turns.
+func (r *RedisSessionStore) GetMaxTurns() int {
+	return r.maxTurns
+}
+
+// GetSession retrieves a conversation session by LINE user ID.
+// Returns nil if the session does not exist or has expired. The key TTL is refreshed for valid sessions.
+func (r *RedisSessionStore) GetSession(userID string) (*domain.ConversationSession, error) {
+	ctx := context.Background()
+	metaKey, messagesKey, personaKey := sessionKeys(userID)
+
+	var metaCmd, personaCmd *goredis.StringCmd
+	var messagesCmd *goredis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		metaCmd = pipe.Get(ctx, metaKey)
+		messagesCmd = pipe.LRange(ctx, messagesKey, 0, -1)
+		personaCmd = pipe.Get(ctx, personaKey)
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		logrus.Errorln(err)
+		return nil, err
+	}
+
+	lastAccess, err := metaCmd.Int64()
+	if err != nil {
+		if errors.Is(err, goredis.Nil) {
+			return nil, nil
+		}
+		// If data is malformed, delete and return nil
+		logrus.Warnf("Discarding malformed session for user %s: %v", userID, err)
+		_ = r.DeleteSession(userID)
+		return nil, nil
+	}
+
+	session := domain.NewConversationSession(userID, r.timeout, r.maxTurns)
+	session.LastAccessTime = time.UnixMilli(lastAccess)
+	session.Persona = personaCmd.Val()
+
+	// Native TTL normally removes expired keys; this covers servers that expire lazily
+	if session.IsExpired() {
+		_ = r.DeleteSession(userID)
+		return nil, nil
+	}
+
+	for _, raw := range messagesCmd.Val() {
+		var msg domain.ChatMessage
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+			logrus.Warnf("Discarding malformed session for user %s: %v", userID, err)
+			_ = r.DeleteSession(userID)
+			return nil, nil
+		}
+		session.Messages = append(session.Messages, msg)
+	}
+
+	// Update LastAccessTime for valid session and slide the TTL
+	session.LastAccessTime = time.Now()
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, metaKey, session.LastAccessTime.UnixMilli(), r.timeout)
+		pipe.PExpire(ctx, messagesKey, r.timeout)
+		pipe.PExpire(ctx, personaKey, r.timeout)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorln(err)
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// UpdateSession creates or updates a conversation session, replacing its stored history atomically.
+// The session's LastAccessTime is updated to the current time before storing.
+func (r *RedisSessionStore) UpdateSession(session *domain.ConversationSession) error {
+	ctx := context.Background()
+	metaKey, messagesKey, personaKey := sessionKeys(session.UserID)
+
+	session.LastAccessTime = time.Now()
+
+	messages, err := encodeMessages(session.Messages...)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, messagesKey)
+		if len(messages) > 0 {
+			pipe.RPush(ctx, messagesKey, messages...)
+			pipe.PExpire(ctx, messagesKey, r.timeout)
+		}
+		if session.Persona != "" {
+			pipe.Set(ctx, personaKey, session.Persona, r.timeout)
+		} else {
+			pipe.Del(ctx, personaKey)
+		}
+		pipe.Set(ctx, metaKey, session.LastAccessTime.UnixMilli(), r.timeout)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
+// AppendTurn atomically appends a turn to the user's session in a single MULTI/EXEC transaction,
+// trimming the history to maxTurns and refreshing the TTL of both keys.
+func (r *RedisSessionStore) AppendTurn(userID string, userMsg, assistantMsg domain.ChatMessage) error {
+	ctx := context.Background()
+	metaKey, messagesKey, personaKey := sessionKeys(userID)
+
+	messages, err := encodeMessages(userMsg, assistantMsg)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.RPush(ctx, messagesKey, messages...)
+		if r.maxTurns > 0 {
+			pipe.LTrim(ctx, messagesKey, int64(-r.maxTurns*2), -1)
+		}
+		pipe.PExpire(ctx, messagesKey, r.timeout)
+		pipe.PExpire(ctx, personaKey, r.timeout)
+		pipe.Set(ctx, metaKey, time.Now().UnixMilli(), r.timeout)
+		return nil
+	})
+	if err != nil {
+		logrus.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSession removes a conversation session by LINE user ID.
+// This operation is idempotent - deleting a non-existent session does not return an error.
+func (r *RedisSessionStore) DeleteSession(userID string) error {
+	metaKey, messagesKey, personaKey := sessionKeys(userID)
+	if err := r.client.Del(context.Background(), metaKey, messagesKey, personaKey).Err(); err != nil {
+		logrus.Errorln(err)
+		return err
+	}
+	return nil
+}
+
+// CountSessions returns the number of sessions that have not expired.
+// Expired sessions are removed by their key TTL, so every metadata key is an active session.
+// Keys are scanned by prefix; the message list and persona keys share it and are skipped.
+func (r *RedisSessionStore) CountSessions() (int, error) {
+	count := 0
+	iter := r.client.Scan(context.Background(), 0, sessionKeyPrefix+"*", 0).Iterator()
+	for iter.Next(context.Background()) {
+		if key := iter.Val(); !strings.HasSuffix(key, ":messages") && !strings.HasSuffix(key, ":persona") {
+			count++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		logrus.Errorln(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// sessionKeys returns the metadata, message list, and persona keys for a user
+func sessionKeys(userID string) (string, string, string) {
+	metaKey := sessionKeyPrefix + userID
+	return metaKey, metaKey + ":messages", metaKey + ":persona"
+}
+
+// encodeMessages JSON-encodes chat messages for storage in a Redis list
+func encodeMessages(messages ...domain.ChatMessage) ([]interface{}, error) {
+	encoded := make([]interface{}, 0, len(messages))
+	for i, msg := range messages {
+		raw, err := json.Marshal(msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal session message %d: %w", i, err)
+		}
+		encoded = append(encoded, string(raw))
+	}
+	return encoded, nil
+}
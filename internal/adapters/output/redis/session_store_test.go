@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang-template/internal/adapters/output/sessionstoretest"
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Default test configuration values
+const (
+	testTimeout  = 30 * time.Minute
+	testMaxTurns = 10
+)
+
+// newTestStore starts an in-process RESP server and returns a store connected to it
+func newTestStore(t *testing.T, timeout time.Duration, maxTurns int) (*RedisSessionStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisSessionStore(client, timeout, maxTurns), server
+}
+
+// TestRedisSessionStoreBehavior runs the shared SessionStore behavioral tests against an in-process RESP server
+func TestRedisSessionStoreBehavior(t *testing.T) {
+	sessionstoretest.Run(t, func(t *testing.T, timeout time.Duration, maxTurns int) output.SessionStore {
+		store, _ := newTestStore(t, timeout, maxTurns)
+		return store
+	})
+}
+
+// TestSessionKeysUseTimeoutAsNativeTTL tests that session keys carry the session timeout as TTL
+func TestSessionKeysUseTimeoutAsNativeTTL(t *testing.T) {
+	store, server := newTestStore(t, testTimeout, testMaxTurns)
+
+	session := domain.NewConversationSession("U1234567890abcdef", testTimeout, testMaxTurns)
+	session.AddTurn(
+		domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+		domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Hi there!"},
+	)
+	if err := store.UpdateSession(session); err != nil {
+		t.Fatalf("expected no error on UpdateSession, got %v", err)
+	}
+
+	metaKey, messagesKey, _ := sessionKeys("U1234567890abcdef")
+	if ttl := server.TTL(metaKey); ttl != testTimeout {
+		t.Errorf("expected meta key TTL %v, got %v", testTimeout, ttl)
+	}
+	if ttl := server.TTL(messagesKey); ttl != testTimeout {
+		t.Errorf("expected messages key TTL %v, got %v", testTimeout, ttl)
+	}
+
+	// Once the TTL passes the server drops the keys and the session is gone
+	server.FastForward(testTimeout + time.Second)
+
+	retrieved, err := store.GetSession("U1234567890abcdef")
+	if err != nil {
+		t.Fatalf("expected no error on GetSession, got %v", err)
+	}
+	if retrieved != nil {
+		t.Error("expected nil after TTL elapsed")
+	}
+}
+
+// TestGetSessionRefreshesTTL tests that reading a session slides its expiry
+func TestGetSessionRefreshesTTL(t *testing.T) {
+	store, server := newTestStore(t, testTimeout, testMaxTurns)
+
+	if err := store.AppendTurn("U1234567890abcdef",
+		domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+		domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Hi there!"},
+	); err != nil {
+		t.Fatalf("expected no error on AppendTurn, got %v", err)
+	}
+
+	server.FastForward(20 * time.Minute)
+
+	if _, err := store.GetSession("U1234567890abcdef"); err != nil {
+		t.Fatalf("expected no error on GetSession, got %v", err)
+	}
+
+	_, messagesKey, _ := sessionKeys("U1234567890abcdef")
+	if ttl := server.TTL(messagesKey); ttl != testTimeout {
+		t.Errorf("expected TTL to be reset to %v, got %v", testTimeout, ttl)
+	}
+}
+
+// TestAppendTurnTrimsToMaxTurns tests that AppendTurn keeps only the most recent maxTurns turns
+func TestAppendTurnTrimsToMaxTurns(t *testing.T) {
+	maxTurns := 2
+	store, _ := newTestStore(t, testTimeout, maxTurns)
+
+	for i := 0; i < 3; i++ {
+		err := store.AppendTurn("U1234567890abcdef",
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "message " + string(rune('A'+i))},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "response " + string(rune('A'+i))},
+		)
+		if err != nil {
+			t.Fatalf("expected no error on AppendTurn, got %v", err)
+		}
+	}
+
+	session, err := store.GetSession("U1234567890abcdef")
+	if err != nil || session == nil {
+		t.Fatalf("expected session, got %v (err %v)", session, err)
+	}
+	if len(session.Messages) != maxTurns*2 {
+		t.Fatalf("expected %d messages, got %d", maxTurns*2, len(session.Messages))
+	}
+	if session.Messages[0].Content != "message B" {
+		t.Errorf("expected first message to be 'message B', got %s", session.Messages[0].Content)
+	}
+	if session.Messages[3].Content != "response C" {
+		t.Errorf("expected last message to be 'response C', got %s", session.Messages[3].Content)
+	}
+}
+
+// TestAppendTurnConcurrentWritersDoNotLoseTurns tests that concurrent appends from
+// several replicas are all kept
+func TestAppendTurnConcurrentWritersDoNotLoseTurns(t *testing.T) {
+	store, _ := newTestStore(t, testTimeout, 100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = store.AppendTurn("U1234567890abcdef",
+				domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "question"},
+				domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "answer"},
+			)
+		}()
+	}
+	wg.Wait()
+
+	session, _ := store.GetSession("U1234567890abcdef")
+	if session == nil || len(session.Messages) != 40 {
+		t.Fatalf("expected 40 messages from 20 concurrent turns, got %+v", session)
+	}
+	for i := 0; i < len(session.Messages); i += 2 {
+		if session.Messages[i].Role != domain.ChatMessageRoleUser || session.Messages[i+1].Role != domain.ChatMessageRoleAssistant {
+			t.Fatalf("expected turns to stay paired at index %d", i)
+		}
+	}
+}
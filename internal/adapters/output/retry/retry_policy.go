@@ -0,0 +1,120 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-template/configs"
+)
+
+// Default retry policy configuration
+const (
+	defaultRetryMaxAttempts  = 30
+	defaultRetryInitialDelay = 1 * time.Second
+	defaultRetryMaxDelay     = 30 * time.Second
+	defaultRetryMultiplier   = 2
+	defaultRetryBudget       = 2 * time.Minute
+)
+
+// jitter picks the actual delay for a backoff delay, a random duration in [0, delay] by default
+// Tests replace it to make delays predictable.
+var jitter = func(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay + 1)
+}
+
+// RetryPolicy struct - How LLM requests are retried after transient failures
+// Delays grow exponentially from InitialDelay up to MaxDelay, with full jitter. A Retry-After
+// header on a 429 or 503 response is used instead of the computed delay.
+type RetryPolicy struct {
+	MaxAttempts  int           // Attempts per request, including the first
+	InitialDelay time.Duration // Delay ceiling after the first failed attempt
+	MaxDelay     time.Duration // Largest delay ceiling; Retry-After may ask for longer
+	Multiplier   float64       // Growth of the delay ceiling per attempt
+	Budget       time.Duration // Time all attempts of a request may take, 0 for no limit
+}
+
+// DefaultRetryPolicy func - Returns the retry policy used when nothing is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  defaultRetryMaxAttempts,
+		InitialDelay: defaultRetryInitialDelay,
+		MaxDelay:     defaultRetryMaxDelay,
+		Multiplier:   defaultRetryMultiplier,
+		Budget:       defaultRetryBudget,
+	}
+}
+
+// NewRetryPolicy func - Creates a retry policy from config, using the default for every value of zero or less
+func NewRetryPolicy(config configs.LMStudioRetry) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if config.MaxAttempts > 0 {
+		policy.MaxAttempts = config.MaxAttempts
+	}
+	if config.InitialDelayMs > 0 {
+		policy.InitialDelay = time.Duration(config.InitialDelayMs) * time.Millisecond
+	}
+	if config.MaxDelay > 0 {
+		policy.MaxDelay = time.Duration(config.MaxDelay) * time.Second
+	}
+	if config.Multiplier >= 1 {
+		policy.Multiplier = config.Multiplier
+	}
+	if config.Budget > 0 {
+		policy.Budget = time.Duration(config.Budget) * time.Second
+	}
+	return policy
+}
+
+// Backoff returns the delay ceiling after the given failed attempt, counting from 1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if delay > float64(p.MaxDelay) || math.IsInf(delay, 0) || math.IsNaN(delay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// Delay returns how long to wait before retrying after the given failed attempt
+// resp is the failed attempt's response, or nil if the request got none.
+func (p RetryPolicy) Delay(attempt int, resp *http.Response) time.Duration {
+	if delay, ok := retryAfter(resp, time.Now()); ok {
+		return delay
+	}
+	return jitter(p.Backoff(attempt))
+}
+
+// RetryableStatus reports whether a response status is worth retrying
+// Server errors are, and so is 429 Too Many Requests; other 4xx client errors are not.
+func (p RetryPolicy) RetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode < 600)
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response
+// The header holds either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
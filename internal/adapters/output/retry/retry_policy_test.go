@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"golang-template/configs"
+)
+
+// TestNewRetryPolicy tests that configured values are used and missing ones fall back to the defaults
+func TestNewRetryPolicy(t *testing.T) {
+	defaults := NewRetryPolicy(configs.LMStudioRetry{})
+	if defaults != DefaultRetryPolicy() {
+		t.Errorf("expected the default policy, got %+v", defaults)
+	}
+
+	policy := NewRetryPolicy(configs.LMStudioRetry{MaxAttempts: 5, InitialDelayMs: 250, MaxDelay: 10, Multiplier: 3, Budget: 60})
+	expected := RetryPolicy{MaxAttempts: 5, InitialDelay: 250 * time.Millisecond, MaxDelay: 10 * time.Second, Multiplier: 3, Budget: time.Minute}
+	if policy != expected {
+		t.Errorf("expected %+v, got %+v", expected, policy)
+	}
+}
+
+// TestRetryPolicyBackoff tests that delay ceilings grow exponentially up to the maximum
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialDelay: time.Second, MaxDelay: 30 * time.Second, Multiplier: 2}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second}
+	for i, want := range expected {
+		if got := policy.Backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+	if got := policy.Backoff(1000); got != 30*time.Second {
+		t.Errorf("expected large attempts capped at 30s, got %v", got)
+	}
+}
+
+// TestRetryPolicyDelayUsesFullJitter tests that delays are spread between zero and the backoff ceiling
+func TestRetryPolicyDelayUsesFullJitter(t *testing.T) {
+	policy := RetryPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+
+	distinct := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		delay := policy.Delay(3, nil)
+		if delay < 0 || delay > 400*time.Millisecond {
+			t.Fatalf("expected a delay within [0, 400ms], got %v", delay)
+		}
+		distinct[delay] = true
+	}
+	if len(distinct) < 2 {
+		t.Error("expected delays to vary")
+	}
+}
+
+// TestRetryAfter tests parsing of Retry-After in seconds and as an HTTP date, only on 429 and 503
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		header     string
+		expected   time.Duration
+		ok         bool
+	}{
+		{"seconds on 429", http.StatusTooManyRequests, "7", 7 * time.Second, true},
+		{"date on 503", http.StatusServiceUnavailable, now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"date in the past", http.StatusServiceUnavailable, now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"ignored on 500", http.StatusInternalServerError, "7", 0, false},
+		{"missing", http.StatusTooManyRequests, "", 0, false},
+		{"invalid", http.StatusTooManyRequests, "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+
+			delay, ok := retryAfter(resp, now)
+
+			if delay != tt.expected || ok != tt.ok {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.ok, delay, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Compile-time check to ensure RoutingClientAdapter implements LMStudioClient interface
+var _ output.LMStudioClient = (*RoutingClientAdapter)(nil)
+
+// Timeout for the ListModels call of each health check
+const healthCheckTimeout = 10 * time.Second
+
+// Backend struct - An OpenAI-compatible LLM server the router can send requests to
+type Backend struct {
+	Name   string                // Shown in logs and Status, e.g. "lmstudio" or "ollama"
+	Client output.LMStudioClient // Adapter for the server
+	Weight int                   // Share of requests; 0 makes the backend a standby used only for failover
+}
+
+// BackendStatus struct - Health of a backend as last observed by the router
+type BackendStatus struct {
+	Name      string
+	Weight    int
+	Healthy   bool
+	LastError string    // Error that marked the backend unhealthy, empty when healthy
+	CheckedAt time.Time // When the health was last updated, zero before the first request or check
+}
+
+// backendState - A backend with its health and smooth weighted round-robin counter
+type backendState struct {
+	Backend
+	healthy       bool
+	lastError     string
+	checkedAt     time.Time
+	currentWeight int
+}
+
+// RoutingClientAdapter struct - Output adapter spreading LLM requests over several backends
+// Requests are balanced over healthy backends by weight. When a backend fails with
+// domain.ErrLMStudioUnavailable it is marked unhealthy and the request fails over to the
+// other backends in configured order. Unhealthy backends are only tried when every healthy
+// one has failed, and are marked healthy again by a successful request or health check.
+type RoutingClientAdapter struct {
+	mu       sync.Mutex
+	backends []*backendState
+
+	// Background health checks, see StartHealthChecks
+	healthMu   sync.Mutex
+	healthStop chan struct{}
+	healthDone chan struct{}
+}
+
+// NewRoutingClientAdapter func - Creates a router over backends, listed in failover order
+// Every backend starts healthy. Returns an error if there are no backends, a backend has no
+// client, or a weight is negative.
+func NewRoutingClientAdapter(backends []Backend) (*RoutingClientAdapter, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("at least one backend is required")
+	}
+
+	states := make([]*backendState, 0, len(backends))
+	for i, backend := range backends {
+		if backend.Client == nil {
+			return nil, fmt.Errorf("backend %d (%s) has no client", i, backend.Name)
+		}
+		if backend.Weight < 0 {
+			return nil, fmt.Errorf("backend %s has negative weight %d", backend.Name, backend.Weight)
+		}
+		if backend.Name == "" {
+			backend.Name = fmt.Sprintf("backend-%d", i+1)
+		}
+		states = append(states, &backendState{Backend: backend, healthy: true})
+	}
+
+	logrus.Infof("Routing client adapter initialized with %d backends", len(states))
+
+	return &RoutingClientAdapter{backends: states}, nil
+}
+
+// ChatCompletion sends a chat completion to the next backend, failing over while backends are unavailable
+func (r *RoutingClientAdapter) ChatCompletion(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	var response *domain.ChatCompletionResponse
+	err := r.route(ctx, "chat completion", func(client output.LMStudioClient) error {
+		var err error
+		response, err = client.ChatCompletion(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+// ChatCompletionStream starts a streaming chat completion on the next backend
+// Failover only happens before streaming begins; errors during the stream are reported in its chunks.
+func (r *RoutingClientAdapter) ChatCompletionStream(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+	var chunks <-chan domain.ChatCompletionChunk
+	err := r.route(ctx, "streaming chat completion", func(client output.LMStudioClient) error {
+		var err error
+		chunks, err = client.ChatCompletionStream(ctx, request)
+		return err
+	})
+	return chunks, err
+}
+
+// ListModels lists the models of the next backend, failing over while backends are unavailable
+func (r *RoutingClientAdapter) ListModels(ctx context.Context) ([]domain.ModelInfo, error) {
+	var models []domain.ModelInfo
+	err := r.route(ctx, "list models", func(client output.LMStudioClient) error {
+		var err error
+		models, err = client.ListModels(ctx)
+		return err
+	})
+	return models, err
+}
+
+// Status returns the health of every backend in configured order
+func (r *RoutingClientAdapter) Status() []BackendStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]BackendStatus, 0, len(r.backends))
+	for _, backend := range r.backends {
+		statuses = append(statuses, BackendStatus{
+			Name:      backend.Name,
+			Weight:    backend.Weight,
+			Healthy:   backend.healthy,
+			LastError: backend.lastError,
+			CheckedAt: backend.checkedAt,
+		})
+	}
+	return statuses
+}
+
+// StartHealthChecks starts a background goroutine that calls ListModels on every backend every interval
+// Backends that answer are marked healthy and backends that fail are marked unhealthy.
+// Calling StartHealthChecks while health checks are already running has no effect.
+func (r *RoutingClientAdapter) StartHealthChecks(interval time.Duration) {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+
+	if r.healthStop != nil || interval <= 0 {
+		return
+	}
+
+	r.healthStop = make(chan struct{})
+	r.healthDone = make(chan struct{})
+
+	go r.runHealthChecks(interval, r.healthStop, r.healthDone)
+}
+
+// StopHealthChecks stops the background health checks and waits for them to exit.
+// This operation is idempotent.
+func (r *RoutingClientAdapter) StopHealthChecks() {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+
+	if r.healthStop == nil {
+		return
+	}
+
+	close(r.healthStop)
+	<-r.healthDone
+	r.healthStop = nil
+	r.healthDone = nil
+}
+
+// CheckHealth calls ListModels on every backend and updates its health
+func (r *RoutingClientAdapter) CheckHealth(ctx context.Context) {
+	for _, backend := range r.backends {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		_, err := backend.Client.ListModels(checkCtx)
+		cancel()
+		r.markHealth(backend, err)
+	}
+}
+
+// runHealthChecks - Checks every backend on every tick until stop is closed
+func (r *RoutingClientAdapter) runHealthChecks(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.CheckHealth(context.Background())
+		}
+	}
+}
+
+// route runs call against backends in routing order until one succeeds
+// Only domain.ErrLMStudioUnavailable fails over; other errors, such as domain.ErrInvalidRequest,
+// are returned as they are since another backend would reject the request too.
+func (r *RoutingClientAdapter) route(ctx context.Context, operation string, call func(client output.LMStudioClient) error) error {
+	var lastErr error
+	for _, backend := range r.candidates() {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled: %w", ctx.Err())
+		}
+
+		err := call(backend.Client)
+		if err == nil {
+			r.markHealth(backend, nil)
+			return nil
+		}
+		if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+			return err
+		}
+
+		r.markHealth(backend, err)
+		logrus.Warnf("Backend %s unavailable for %s, failing over: %v", backend.Name, operation, err)
+		lastErr = err
+	}
+
+	return fmt.Errorf("all %d backends failed, last error: %w", len(r.backends), lastErr)
+}
+
+// candidates returns the backends in the order a request tries them
+// The backend chosen by weight comes first, followed by the other healthy backends and then
+// the unhealthy ones, each in configured order.
+func (r *RoutingClientAdapter) candidates() []*backendState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	first := r.pickWeighted()
+	candidates := make([]*backendState, 0, len(r.backends))
+	if first != nil {
+		candidates = append(candidates, first)
+	}
+	for _, healthy := range []bool{true, false} {
+		for _, backend := range r.backends {
+			if backend != first && backend.healthy == healthy {
+				candidates = append(candidates, backend)
+			}
+		}
+	}
+	return candidates
+}
+
+// pickWeighted chooses a healthy backend by smooth weighted round-robin, or nil if none has weight
+// Must be called with the lock held.
+func (r *RoutingClientAdapter) pickWeighted() *backendState {
+	var best *backendState
+	total := 0
+	for _, backend := range r.backends {
+		if !backend.healthy || backend.Weight == 0 {
+			continue
+		}
+		backend.currentWeight += backend.Weight
+		total += backend.Weight
+		if best == nil || backend.currentWeight > best.currentWeight {
+			best = backend
+		}
+	}
+	if best != nil {
+		best.currentWeight -= total
+	}
+	return best
+}
+
+// markHealth records the result of a request or health check, logging health changes
+func (r *RoutingClientAdapter) markHealth(backend *backendState, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	healthy := err == nil
+	if backend.healthy != healthy {
+		if healthy {
+			logrus.Infof("Backend %s is healthy again", backend.Name)
+		} else {
+			logrus.Warnf("Backend %s marked unhealthy: %v", backend.Name, err)
+		}
+	}
+
+	backend.healthy = healthy
+	backend.checkedAt = time.Now()
+	backend.lastError = ""
+	if err != nil {
+		backend.lastError = err.Error()
+	}
+}
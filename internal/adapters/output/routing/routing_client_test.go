@@ -0,0 +1,249 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-template/internal/domain"
+)
+
+// fakeBackend implements output.LMStudioClient, answering with its name or failing with err
+type fakeBackend struct {
+	name string
+
+	mu    sync.Mutex
+	err   error
+	calls int
+}
+
+func (f *fakeBackend) result() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.err
+}
+
+func (f *fakeBackend) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *fakeBackend) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeBackend) ChatCompletion(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	if err := f.result(); err != nil {
+		return nil, err
+	}
+	return &domain.ChatCompletionResponse{Content: f.name}, nil
+}
+
+func (f *fakeBackend) ChatCompletionStream(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+	if err := f.result(); err != nil {
+		return nil, err
+	}
+	chunks := make(chan domain.ChatCompletionChunk, 1)
+	chunks <- domain.ChatCompletionChunk{Content: f.name, Done: true}
+	close(chunks)
+	return chunks, nil
+}
+
+func (f *fakeBackend) ListModels(ctx context.Context) ([]domain.ModelInfo, error) {
+	if err := f.result(); err != nil {
+		return nil, err
+	}
+	return []domain.ModelInfo{{ID: f.name + "-model"}}, nil
+}
+
+// unavailable returns an error wrapping domain.ErrLMStudioUnavailable
+func unavailable() error {
+	return fmt.Errorf("%w: connection refused", domain.ErrLMStudioUnavailable)
+}
+
+// newTestRouter creates a router over the fake backends with the given weights
+func newTestRouter(t *testing.T, backends []*fakeBackend, weights ...int) *RoutingClientAdapter {
+	t.Helper()
+	configured := make([]Backend, len(backends))
+	for i, backend := range backends {
+		configured[i] = Backend{Name: backend.name, Client: backend, Weight: weights[i]}
+	}
+	router, err := NewRoutingClientAdapter(configured)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return router
+}
+
+// chat sends a chat completion and returns the name of the backend that answered
+func chat(t *testing.T, router *RoutingClientAdapter) string {
+	t.Helper()
+	response, err := router.ChatCompletion(context.Background(), domain.ChatCompletionRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return response.Content
+}
+
+// TestNewRoutingClientAdapterValidatesBackends tests that invalid backend lists are rejected
+func TestNewRoutingClientAdapterValidatesBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []Backend
+	}{
+		{"no backends", nil},
+		{"missing client", []Backend{{Name: "lmstudio", Weight: 1}}},
+		{"negative weight", []Backend{{Name: "lmstudio", Client: &fakeBackend{}, Weight: -1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRoutingClientAdapter(tt.backends); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+// TestRoutingBalancesByWeight tests that healthy backends share requests in proportion to their weights
+func TestRoutingBalancesByWeight(t *testing.T) {
+	a, b, standby := &fakeBackend{name: "a"}, &fakeBackend{name: "b"}, &fakeBackend{name: "standby"}
+	router := newTestRouter(t, []*fakeBackend{a, b, standby}, 3, 1, 0)
+
+	counts := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		counts[chat(t, router)]++
+	}
+
+	if counts["a"] != 6 || counts["b"] != 2 {
+		t.Errorf("expected a:b of 6:2, got %v", counts)
+	}
+	if standby.callCount() != 0 {
+		t.Errorf("expected standby backend unused while others are healthy, got %d calls", standby.callCount())
+	}
+}
+
+// TestRoutingFailsOverOnUnavailable tests that an unavailable backend is marked unhealthy and skipped
+func TestRoutingFailsOverOnUnavailable(t *testing.T) {
+	// Arrange
+	a, b := &fakeBackend{name: "a", err: unavailable()}, &fakeBackend{name: "b"}
+	router := newTestRouter(t, []*fakeBackend{a, b}, 1, 1)
+
+	// Act
+	first := chat(t, router)
+	second := chat(t, router)
+
+	// Assert
+	if first != "b" || second != "b" {
+		t.Errorf("expected both requests answered by b, got %s and %s", first, second)
+	}
+	if a.callCount() != 1 {
+		t.Errorf("expected unhealthy backend a to be skipped after failing, got %d calls", a.callCount())
+	}
+	status := router.Status()
+	if status[0].Healthy || status[0].LastError == "" || !status[1].Healthy {
+		t.Errorf("expected a unhealthy and b healthy, got %+v", status)
+	}
+}
+
+// TestRoutingUsesStandbyAndRecovers tests that standby and unhealthy backends are tried last and recover on success
+func TestRoutingUsesStandbyAndRecovers(t *testing.T) {
+	a, standby := &fakeBackend{name: "a", err: unavailable()}, &fakeBackend{name: "standby"}
+	router := newTestRouter(t, []*fakeBackend{a, standby}, 1, 0)
+
+	if answered := chat(t, router); answered != "standby" {
+		t.Errorf("expected standby to answer while a is down, got %s", answered)
+	}
+
+	// With no healthy weighted backend left, a is tried again after the standby only if the standby fails
+	standby.setErr(unavailable())
+	a.setErr(nil)
+	if answered := chat(t, router); answered != "a" {
+		t.Errorf("expected recovered a to answer, got %s", answered)
+	}
+	if !router.Status()[0].Healthy {
+		t.Error("expected a to be marked healthy after answering")
+	}
+}
+
+// TestRoutingDoesNotFailOverOnOtherErrors tests that errors other than unavailable are returned at once
+func TestRoutingDoesNotFailOverOnOtherErrors(t *testing.T) {
+	invalid := fmt.Errorf("%w: status 400 - bad model", domain.ErrInvalidRequest)
+	a, b := &fakeBackend{name: "a", err: invalid}, &fakeBackend{name: "b"}
+	router := newTestRouter(t, []*fakeBackend{a, b}, 1, 0)
+
+	_, err := router.ChatCompletion(context.Background(), domain.ChatCompletionRequest{})
+
+	if !errors.Is(err, domain.ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", err)
+	}
+	if b.callCount() != 0 {
+		t.Error("expected no failover for an invalid request")
+	}
+	if !router.Status()[0].Healthy {
+		t.Error("expected a to stay healthy")
+	}
+}
+
+// TestRoutingAllBackendsUnavailable tests that the last unavailable error is returned when every backend fails
+func TestRoutingAllBackendsUnavailable(t *testing.T) {
+	a, b := &fakeBackend{name: "a", err: unavailable()}, &fakeBackend{name: "b", err: unavailable()}
+	router := newTestRouter(t, []*fakeBackend{a, b}, 1, 1)
+
+	_, err := router.ChatCompletionStream(context.Background(), domain.ChatCompletionRequest{})
+
+	if !errors.Is(err, domain.ErrLMStudioUnavailable) {
+		t.Errorf("expected ErrLMStudioUnavailable, got %v", err)
+	}
+	if a.callCount() != 1 || b.callCount() != 1 {
+		t.Errorf("expected each backend tried once, got a=%d b=%d", a.callCount(), b.callCount())
+	}
+}
+
+// TestRoutingStopsWhenContextCancelled tests that failover stops once the caller gives up
+func TestRoutingStopsWhenContextCancelled(t *testing.T) {
+	a, b := &fakeBackend{name: "a", err: unavailable()}, &fakeBackend{name: "b"}
+	router := newTestRouter(t, []*fakeBackend{a, b}, 1, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := router.ListModels(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if a.callCount() != 0 || b.callCount() != 0 {
+		t.Error("expected no backend to be called")
+	}
+}
+
+// TestCheckHealthUpdatesBackends tests that health checks mark backends by their ListModels result
+func TestCheckHealthUpdatesBackends(t *testing.T) {
+	a, b := &fakeBackend{name: "a", err: unavailable()}, &fakeBackend{name: "b"}
+	router := newTestRouter(t, []*fakeBackend{a, b}, 1, 1)
+
+	router.CheckHealth(context.Background())
+	status := router.Status()
+	if status[0].Healthy || !status[1].Healthy || status[1].CheckedAt.IsZero() {
+		t.Fatalf("expected a unhealthy and b healthy, got %+v", status)
+	}
+
+	a.setErr(nil)
+	router.StartHealthChecks(10 * time.Millisecond)
+	defer router.StopHealthChecks()
+
+	deadline := time.Now().Add(time.Second)
+	for !router.Status()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("expected background health check to mark a healthy")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
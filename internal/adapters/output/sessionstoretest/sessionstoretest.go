@@ -0,0 +1,299 @@
+// Package sessionstoretest provides the behavioral test suite shared by all
+// output.SessionStore adapters, so every driver honors the same contract.
+package sessionstoretest
+
+import (
+	"testing"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+)
+
+// Factory creates a fresh store configured with the given session timeout and max turns
+type Factory func(t *testing.T, timeout time.Duration, maxTurns int) output.SessionStore
+
+// Default configuration values used by the suite
+const (
+	testTimeout  = 30 * time.Minute
+	testMaxTurns = 10
+)
+
+// Run executes the SessionStore behavioral tests against stores built by newStore.
+// User IDs are derived from the subtest name so adapters backed by shared storage
+// do not see data from other subtests.
+func Run(t *testing.T, newStore Factory) {
+	t.Run("GetSessionReturnsNilForNonExistentUser", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+
+		session, err := store.GetSession(userID(t))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if session != nil {
+			t.Errorf("expected nil session for non-existent user, got %+v", session)
+		}
+	})
+
+	t.Run("UpdateSessionCreatesNewSession", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		session := domain.NewConversationSession(id, testTimeout, testMaxTurns)
+		session.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Hi there!"},
+		)
+		if err := store.UpdateSession(session); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+
+		retrieved, err := store.GetSession(id)
+		if err != nil {
+			t.Fatalf("expected no error on GetSession, got %v", err)
+		}
+		if retrieved == nil {
+			t.Fatal("expected session to exist after UpdateSession")
+		}
+		if retrieved.UserID != id {
+			t.Errorf("expected UserID %s, got %s", id, retrieved.UserID)
+		}
+
+		history := retrieved.GetHistory()
+		if len(history) != 2 {
+			t.Fatalf("expected 2 messages, got %d", len(history))
+		}
+		if history[0].Role != domain.ChatMessageRoleUser || history[0].Content != "Hello" {
+			t.Errorf("expected first message to be user 'Hello', got %+v", history[0])
+		}
+		if history[1].Role != domain.ChatMessageRoleAssistant || history[1].Content != "Hi there!" {
+			t.Errorf("expected second message to be assistant 'Hi there!', got %+v", history[1])
+		}
+	})
+
+	t.Run("GetSessionUpdatesLastAccessTime", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		if err := store.UpdateSession(domain.NewConversationSession(id, testTimeout, testMaxTurns)); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+
+		before := time.Now()
+		time.Sleep(10 * time.Millisecond)
+
+		retrieved, err := store.GetSession(id)
+		if err != nil {
+			t.Fatalf("expected no error on GetSession, got %v", err)
+		}
+		if retrieved == nil {
+			t.Fatal("expected session to exist")
+		}
+		if retrieved.LastAccessTime.Before(before) {
+			t.Errorf("expected LastAccessTime to be refreshed, got %v (before %v)", retrieved.LastAccessTime, before)
+		}
+	})
+
+	t.Run("ExpiredSessionIsTreatedAsNew", func(t *testing.T) {
+		timeout := 50 * time.Millisecond
+		store := newStore(t, timeout, testMaxTurns)
+		id := userID(t)
+
+		session := domain.NewConversationSession(id, timeout, testMaxTurns)
+		session.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Hi there!"},
+		)
+		if err := store.UpdateSession(session); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+
+		time.Sleep(2 * timeout)
+
+		retrieved, err := store.GetSession(id)
+		if err != nil {
+			t.Fatalf("expected no error on GetSession, got %v", err)
+		}
+		if retrieved != nil {
+			t.Error("expected nil for expired session")
+		}
+
+		// A new session for the same user starts with no history
+		if err := store.UpdateSession(domain.NewConversationSession(id, timeout, testMaxTurns)); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+		retrieved, _ = store.GetSession(id)
+		if retrieved == nil || len(retrieved.Messages) != 0 {
+			t.Errorf("expected fresh session with no history, got %+v", retrieved)
+		}
+	})
+
+	t.Run("UpdateSessionOverwritesExistingSession", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		session := domain.NewConversationSession(id, testTimeout, testMaxTurns)
+		session.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "first"},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "first reply"},
+		)
+		_ = store.UpdateSession(session)
+
+		retrieved, _ := store.GetSession(id)
+		retrieved.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "second"},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "second reply"},
+		)
+		if err := store.UpdateSession(retrieved); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+
+		final, _ := store.GetSession(id)
+		if final == nil || len(final.Messages) != 4 {
+			t.Fatalf("expected 4 messages after second update, got %+v", final)
+		}
+		if final.Messages[3].Content != "second reply" {
+			t.Errorf("expected last message 'second reply', got %s", final.Messages[3].Content)
+		}
+	})
+
+	t.Run("RetrievedSessionKeepsConfiguredMaxTurns", func(t *testing.T) {
+		maxTurns := 2
+		store := newStore(t, testTimeout, maxTurns)
+		id := userID(t)
+
+		_ = store.UpdateSession(domain.NewConversationSession(id, testTimeout, maxTurns))
+
+		for i := 0; i < 3; i++ {
+			retrieved, err := store.GetSession(id)
+			if err != nil || retrieved == nil {
+				t.Fatalf("expected session to exist, got %v (err %v)", retrieved, err)
+			}
+			retrieved.AddTurn(
+				domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "message " + string(rune('A'+i))},
+				domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "response " + string(rune('A'+i))},
+			)
+			_ = store.UpdateSession(retrieved)
+		}
+
+		final, _ := store.GetSession(id)
+		if len(final.Messages) != maxTurns*2 {
+			t.Fatalf("expected %d messages, got %d", maxTurns*2, len(final.Messages))
+		}
+		if final.Messages[0].Content != "message B" {
+			t.Errorf("expected oldest turn to be trimmed, first message is %s", final.Messages[0].Content)
+		}
+	})
+
+	t.Run("SessionsAreIsolatedPerUser", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		idA := userID(t) + "-a"
+		idB := userID(t) + "-b"
+
+		session := domain.NewConversationSession(idA, testTimeout, testMaxTurns)
+		session.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Hi there!"},
+		)
+		_ = store.UpdateSession(session)
+
+		other, err := store.GetSession(idB)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if other != nil {
+			t.Errorf("expected no session for a different user, got %+v", other)
+		}
+	})
+
+	t.Run("DeleteSessionRemovesSession", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		_ = store.UpdateSession(domain.NewConversationSession(id, testTimeout, testMaxTurns))
+
+		if err := store.DeleteSession(id); err != nil {
+			t.Fatalf("expected no error on DeleteSession, got %v", err)
+		}
+
+		retrieved, err := store.GetSession(id)
+		if err != nil {
+			t.Fatalf("expected no error on GetSession, got %v", err)
+		}
+		if retrieved != nil {
+			t.Error("expected nil after DeleteSession")
+		}
+	})
+
+	t.Run("DeleteSessionIsIdempotent", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		if err := store.DeleteSession(id); err != nil {
+			t.Errorf("expected no error deleting non-existent session, got %v", err)
+		}
+		if err := store.DeleteSession(id); err != nil {
+			t.Errorf("expected no error on repeated delete, got %v", err)
+		}
+	})
+
+	t.Run("UpdateSessionStoresPersona", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		id := userID(t)
+
+		session := domain.NewConversationSession(id, testTimeout, testMaxTurns)
+		session.Persona = "tutor"
+		if err := store.UpdateSession(session); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+
+		retrieved, err := store.GetSession(id)
+		if err != nil || retrieved == nil {
+			t.Fatalf("expected session, got %v (err %v)", retrieved, err)
+		}
+		if retrieved.Persona != "tutor" {
+			t.Errorf("expected persona tutor, got %q", retrieved.Persona)
+		}
+
+		// Clearing the persona returns the session to the default system prompt
+		retrieved.Persona = ""
+		if err := store.UpdateSession(retrieved); err != nil {
+			t.Fatalf("expected no error on UpdateSession, got %v", err)
+		}
+		cleared, _ := store.GetSession(id)
+		if cleared == nil || cleared.Persona != "" {
+			t.Errorf("expected persona cleared, got %+v", cleared)
+		}
+	})
+
+	t.Run("CountSessionsCountsActiveSessions", func(t *testing.T) {
+		store := newStore(t, testTimeout, testMaxTurns)
+		counter, ok := store.(output.SessionCounter)
+		if !ok {
+			t.Skip("store does not implement output.SessionCounter")
+		}
+		id := userID(t)
+
+		// Shared storage may hold other sessions, so compare against the count before
+		before, err := counter.CountSessions()
+		if err != nil {
+			t.Fatalf("expected no error on CountSessions, got %v", err)
+		}
+		_ = store.UpdateSession(domain.NewConversationSession(id, testTimeout, testMaxTurns))
+		_ = store.UpdateSession(domain.NewConversationSession(id+"-b", testTimeout, testMaxTurns))
+		_ = store.DeleteSession(id + "-b")
+
+		after, err := counter.CountSessions()
+		if err != nil {
+			t.Fatalf("expected no error on CountSessions, got %v", err)
+		}
+		if after != before+1 {
+			t.Errorf("expected %d sessions, got %d", before+1, after)
+		}
+	})
+}
+
+// userID returns a user ID unique to the running subtest
+func userID(t *testing.T) string {
+	return "U-" + t.Name()
+}
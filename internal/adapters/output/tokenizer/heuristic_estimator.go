@@ -0,0 +1,41 @@
+package tokenizer
+
+import (
+	"unicode/utf8"
+
+	"golang-template/internal/ports/output"
+)
+
+// Compile-time check to ensure HeuristicTokenEstimator implements TokenEstimator interface
+var _ output.TokenEstimator = (*HeuristicTokenEstimator)(nil)
+
+// asciiCharsPerToken is the average number of ASCII characters per token for BPE tokenizers
+const asciiCharsPerToken = 4
+
+// HeuristicTokenEstimator struct - Output adapter estimating tokens without a model tokenizer
+// ASCII text is counted at roughly four characters per token. Every non-ASCII rune
+// (Thai, Japanese, emoji, ...) is counted as a full token, since BPE vocabularies
+// usually split these into one or more tokens each.
+type HeuristicTokenEstimator struct{}
+
+// NewHeuristicTokenEstimator func - Creates new heuristic token estimator
+func NewHeuristicTokenEstimator() *HeuristicTokenEstimator {
+	return &HeuristicTokenEstimator{}
+}
+
+// EstimateTokens returns the estimated number of tokens in text
+func (e *HeuristicTokenEstimator) EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for i := 0; i < len(text); {
+		if text[i] < utf8.RuneSelf {
+			ascii++
+			i++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		other++
+		i += size
+	}
+
+	return (ascii+asciiCharsPerToken-1)/asciiCharsPerToken + other
+}
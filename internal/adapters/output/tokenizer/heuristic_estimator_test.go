@@ -0,0 +1,28 @@
+package tokenizer
+
+import "testing"
+
+// TestHeuristicTokenEstimator tests token estimates for ASCII and non-ASCII text
+func TestHeuristicTokenEstimator(t *testing.T) {
+	estimator := NewHeuristicTokenEstimator()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 0},
+		{name: "short ascii rounds up", text: "Hi", expected: 1},
+		{name: "ascii", text: "Hello, how are you?", expected: 5},
+		{name: "thai", text: "สวัสดี", expected: 6},
+		{name: "mixed", text: "hello こんにちは", expected: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimator.EstimateTokens(tt.text); got != tt.expected {
+				t.Errorf("expected %d tokens, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package transcription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Compile-time check to ensure OpenAITranscriberAdapter implements Transcriber interface
+var _ output.Transcriber = (*OpenAITranscriberAdapter)(nil)
+
+// Model requested when none is configured; speech servers commonly alias it to their default model
+const defaultTranscriptionModel = "whisper-1"
+
+// File extension used when the audio content type is unknown (LINE voice messages are M4A)
+const defaultAudioExtension = ".m4a"
+
+// OpenAITranscriberAdapter struct - Output adapter for OpenAI-compatible /v1/audio/transcriptions servers
+type OpenAITranscriberAdapter struct {
+	httpClient *http.Client
+	baseURL    string
+	model      string
+	language   string
+}
+
+// NewOpenAITranscriberAdapter func - Creates new speech-to-text adapter
+func NewOpenAITranscriberAdapter(config configs.Transcription) (*OpenAITranscriberAdapter, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("transcription base URL is required")
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if config.Timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	model := config.Model
+	if model == "" {
+		model = defaultTranscriptionModel
+	}
+
+	adapter := &OpenAITranscriberAdapter{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
+		model:      model,
+		language:   config.Language,
+	}
+
+	logrus.Infof("Transcription adapter initialized with base URL: %s, model: %s, timeout: %v", adapter.baseURL, model, timeout)
+
+	return adapter, nil
+}
+
+// Transcribe uploads the audio as multipart form data and returns the transcript
+func (a *OpenAITranscriberAdapter) Transcribe(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error) {
+	model := a.model
+	if request.Model != nil && *request.Model != "" {
+		model = *request.Model
+	}
+
+	language := request.Language
+	if language == "" {
+		language = a.language
+	}
+
+	body, contentType, err := buildTranscriptionForm(request, model, language)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transcription request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/audio/transcriptions", a.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrTranscriptionUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode < 500 {
+			return nil, fmt.Errorf("%w: status %d - %s", domain.ErrInvalidRequest, resp.StatusCode, string(respBody))
+		}
+		return nil, fmt.Errorf("%w: status %d - %s", domain.ErrTranscriptionUnavailable, resp.StatusCode, string(respBody))
+	}
+
+	var apiResp transcriptionAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+
+	logrus.Infof("Transcription successful, model: %s, characters: %d", model, len(apiResp.Text))
+
+	return &domain.TranscriptionResponse{
+		Text: strings.TrimSpace(apiResp.Text),
+	}, nil
+}
+
+// buildTranscriptionForm - Helper to encode the audio and options as multipart form data
+// Returns the body and its Content-Type header value.
+func buildTranscriptionForm(request domain.TranscriptionRequest, model, language string) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	file, err := writer.CreateFormFile("file", "audio"+audioExtension(request.ContentType))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := file.Write(request.Audio); err != nil {
+		return nil, "", err
+	}
+
+	fields := map[string]string{
+		"model":           model,
+		"response_format": "json",
+	}
+	if language != "" {
+		fields["language"] = language
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
+
+// audioExtension - Helper to pick a file extension for the audio content type
+// Speech servers use the file name to detect the audio format.
+func audioExtension(contentType string) string {
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "audio/m4a", "audio/x-m4a", "audio/mp4":
+		return ".m4a"
+	case "":
+		return defaultAudioExtension
+	}
+
+	if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 {
+		return extensions[0]
+	}
+	return defaultAudioExtension
+}
+
+// transcriptionAPIResponse represents the JSON response of /v1/audio/transcriptions
+type transcriptionAPIResponse struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,124 @@
+package transcription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/configs"
+	"golang-template/internal/domain"
+)
+
+// TestTranscribeSendsMultipartForm tests that audio and options are uploaded as multipart form data
+func TestTranscribeSendsMultipartForm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("expected path /v1/audio/transcriptions, got: %s", r.URL.Path)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("failed to parse multipart form: %v", err)
+		}
+
+		if got := r.FormValue("model"); got != "whisper-large-v3" {
+			t.Errorf("expected model whisper-large-v3, got: %s", got)
+		}
+		if got := r.FormValue("language"); got != "th" {
+			t.Errorf("expected language th, got: %s", got)
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("expected file field: %v", err)
+		}
+		defer file.Close()
+		if header.Filename != "audio.m4a" {
+			t.Errorf("expected file name audio.m4a, got: %s", header.Filename)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "voice-bytes" {
+			t.Errorf("expected uploaded audio, got: %q", data)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"text":" Hello there. "}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewOpenAITranscriberAdapter(configs.Transcription{BaseURL: server.URL + "/", Model: "whisper-large-v3", Language: "th"})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	response, err := adapter.Transcribe(context.Background(), domain.TranscriptionRequest{
+		Audio:       []byte("voice-bytes"),
+		ContentType: "audio/m4a",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if response.Text != "Hello there." {
+		t.Errorf("expected trimmed transcript 'Hello there.', got: %q", response.Text)
+	}
+}
+
+// TestTranscribeErrorMapping tests that server errors map to ErrTranscriptionUnavailable
+// and client errors map to ErrInvalidRequest
+func TestTranscribeErrorMapping(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected error
+	}{
+		{http.StatusInternalServerError, domain.ErrTranscriptionUnavailable},
+		{http.StatusServiceUnavailable, domain.ErrTranscriptionUnavailable},
+		{http.StatusBadRequest, domain.ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			adapter, err := NewOpenAITranscriberAdapter(configs.Transcription{BaseURL: server.URL})
+			if err != nil {
+				t.Fatalf("failed to create adapter: %v", err)
+			}
+
+			_, err = adapter.Transcribe(context.Background(), domain.TranscriptionRequest{Audio: []byte("x")})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got: %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestTranscribeUnreachableServer tests that connection failures map to ErrTranscriptionUnavailable
+func TestTranscribeUnreachableServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	adapter, err := NewOpenAITranscriberAdapter(configs.Transcription{BaseURL: url, Timeout: 1})
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+
+	_, err = adapter.Transcribe(context.Background(), domain.TranscriptionRequest{Audio: []byte("x")})
+	if !errors.Is(err, domain.ErrTranscriptionUnavailable) {
+		t.Errorf("expected ErrTranscriptionUnavailable, got: %v", err)
+	}
+}
+
+// TestNewOpenAITranscriberAdapterRequiresBaseURL tests that a base URL is required
+func TestNewOpenAITranscriberAdapterRequiresBaseURL(t *testing.T) {
+	if _, err := NewOpenAITranscriberAdapter(configs.Transcription{}); err == nil {
+		t.Error("expected error for empty base URL")
+	}
+}
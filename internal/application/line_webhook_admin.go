@@ -0,0 +1,186 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Timeout for listing LM Studio models in /model
+const adminListModelsTimeout = 10 * time.Second
+
+// Argument of /model and /prompt that restores the configured setting
+const adminResetArgument = "reset"
+
+// WithAdmins func - Allows the given LINE user IDs to run the admin commands
+// Admin commands (/model, /stats, /broadcast, /prompt) are refused and hidden from /help for everyone else.
+func WithAdmins(userIDs []string) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.admins = make(map[string]bool, len(userIDs))
+		for _, userID := range userIDs {
+			s.admins[userID] = true
+		}
+	}
+}
+
+// isAdmin - Permission check for the admin commands
+func (s *LineWebhookService) isAdmin(cmd CommandContext) bool {
+	return cmd.UserID != "" && s.admins[cmd.UserID]
+}
+
+// currentModel - Helper method to get the model requested from LM Studio, empty for the LM Studio default
+func (s *LineWebhookService) currentModel() string {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.model
+}
+
+// currentSystemPrompt - Helper method to get the system prompt sent with every request
+func (s *LineWebhookService) currentSystemPrompt() string {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.systemPrompt
+}
+
+// adminCommands - Helper method to create the operational commands restricted to admins
+func (s *LineWebhookService) adminCommands() []Command {
+	return []Command{
+		{
+			Name:        "model",
+			Usage:       "/model [<model>|reset]",
+			Description: "Show or switch the LM Studio model",
+			Permission:  s.isAdmin,
+			Handler:     s.modelCommand,
+		},
+		{
+			Name:        "stats",
+			Description: "Show session counts",
+			Permission:  s.isAdmin,
+			Handler:     s.statsCommand,
+		},
+		{
+			Name:        "broadcast",
+			Usage:       "/broadcast <text>",
+			Description: "Send a message to every friend",
+			MinArgs:     1,
+			Permission:  s.isAdmin,
+			Handler:     s.broadcastCommand,
+		},
+		{
+			Name:        "prompt",
+			Usage:       "/prompt [<text>|reset]",
+			Description: "Show or change the system prompt",
+			Permission:  s.isAdmin,
+			Handler:     s.promptCommand,
+		},
+	}
+}
+
+// modelCommand - Business logic for /model
+// Without arguments, lists the models LM Studio offers. With a model ID, switches to it
+// if LM Studio offers it; "reset" restores the configured model.
+func (s *LineWebhookService) modelCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	if strings.EqualFold(cmd.RawArgs, adminResetArgument) {
+		s.settingsMu.Lock()
+		s.model = s.defaultModel
+		s.settingsMu.Unlock()
+		logrus.Infof("Model reset by %s: model=%q", cmd.UserID, s.defaultModel)
+		return textMessages("Model reset to " + displayModel(s.defaultModel) + ".")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), adminListModelsTimeout)
+	defer cancel()
+
+	models, err := s.lmStudioClient.ListModels(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to list models: %v", err)
+		return textMessages("Couldn't list LM Studio models right now. Please try again later.")
+	}
+
+	if cmd.RawArgs == "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "Current model: %s\nAvailable models:", displayModel(s.currentModel()))
+		for _, model := range models {
+			builder.WriteString("\n- " + model.ID)
+		}
+		return textMessages(builder.String())
+	}
+
+	for _, model := range models {
+		if model.ID == cmd.RawArgs {
+			s.settingsMu.Lock()
+			s.model = model.ID
+			s.settingsMu.Unlock()
+			logrus.Infof("Model switched by %s: model=%q", cmd.UserID, model.ID)
+			return textMessages("Model switched to " + model.ID + ".")
+		}
+	}
+
+	return textMessages(fmt.Sprintf("Model not found: %s\nType /model to see available models", cmd.RawArgs))
+}
+
+// statsCommand - Business logic for /stats
+func (s *LineWebhookService) statsCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	counter, ok := s.sessionStore.(output.SessionCounter)
+	if !ok {
+		return textMessages("Session counts are not available for this session store.")
+	}
+
+	count, err := counter.CountSessions()
+	if err != nil {
+		logrus.Errorf("Failed to count sessions: %v", err)
+		return textMessages("Couldn't count sessions right now. Please try again later.")
+	}
+
+	return textMessages(fmt.Sprintf("Active sessions: %d\nModel: %s", count, displayModel(s.currentModel())))
+}
+
+// broadcastCommand - Business logic for /broadcast
+func (s *LineWebhookService) broadcastCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	request := domain.LineBroadcastRequest{Messages: textMessages(cmd.RawArgs)}
+	if _, err := s.lineClient.BroadcastMessage(request); err != nil {
+		logrus.Errorf("Failed to broadcast message from %s: %v", cmd.UserID, err)
+		return textMessages("Broadcast failed. Please try again later.")
+	}
+
+	logrus.Infof("Broadcast sent by %s", cmd.UserID)
+	return textMessages("Broadcast sent.")
+}
+
+// promptCommand - Business logic for /prompt
+// Without arguments, shows the system prompt. With text, replaces it for every conversation;
+// "reset" restores the configured prompt.
+func (s *LineWebhookService) promptCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	switch {
+	case cmd.RawArgs == "":
+		return textMessages("Current system prompt:\n" + s.currentSystemPrompt())
+
+	case strings.EqualFold(cmd.RawArgs, adminResetArgument):
+		s.settingsMu.Lock()
+		s.systemPrompt = s.defaultSystemPrompt
+		s.settingsMu.Unlock()
+		logrus.Infof("System prompt reset by %s", cmd.UserID)
+		return textMessages("System prompt reset.")
+
+	default:
+		s.settingsMu.Lock()
+		s.systemPrompt = cmd.RawArgs
+		s.settingsMu.Unlock()
+		logrus.Infof("System prompt changed by %s: %s", cmd.UserID, cmd.RawArgs)
+		return textMessages("System prompt updated.")
+	}
+}
+
+// displayModel - Helper to name a model for admin replies
+func displayModel(model string) string {
+	if model == "" {
+		return "LM Studio default"
+	}
+	return model
+}
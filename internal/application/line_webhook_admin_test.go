@@ -0,0 +1,221 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// testAdminUserID is a LINE user allowed to run the admin commands
+const testAdminUserID = "admin-user-id"
+
+// countingSessionStore is a MockSessionStore that also implements output.SessionCounter
+type countingSessionStore struct {
+	MockSessionStore
+	count int
+	err   error
+}
+
+func (c *countingSessionStore) CountSessions() (int, error) {
+	return c.count, c.err
+}
+
+// newAdminTestService creates a service with the test admin and the given LM Studio client and session store
+func newAdminTestService(lineClient *MockLineClient, lmStudioClient *MockLMStudioClient, sessionStore *countingSessionStore) *LineWebhookService {
+	return NewLineWebhookService(lineClient, lmStudioClient, sessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithModel("qwen2.5-7b-instruct"),
+		WithAdmins([]string{testAdminUserID}))
+}
+
+// sendAdminCommand sends text from the test admin and returns the reply text
+func sendAdminCommand(t *testing.T, service *LineWebhookService, lineClient *MockLineClient, text string) string {
+	t.Helper()
+	event := createTextMessageEvent(text)
+	event.Source.UserID = testAdminUserID
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if lineClient.LastReplyRequest == nil {
+		t.Fatal("Expected a reply")
+	}
+	return lineClient.LastReplyRequest.Messages[0].Text
+}
+
+// testModels returns the models offered by the mock LM Studio
+func testModels(ctx context.Context) ([]domain.ModelInfo, error) {
+	return []domain.ModelInfo{{ID: "qwen2.5-7b-instruct"}, {ID: "llama-3.2-3b-instruct"}}, nil
+}
+
+// TestAdminCommands_RestrictedToAdmins tests that other users can neither run nor see the admin commands
+func TestAdminCommands_RestrictedToAdmins(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newAdminTestService(mockLineClient, &MockLMStudioClient{ListModelsFunc: testModels}, &countingSessionStore{})
+
+	for _, text := range []string{"/model", "/stats", "/broadcast Hello", "/prompt Be brief"} {
+		// Act
+		reply := sendCommand(t, service, mockLineClient, text)
+
+		// Assert
+		if !strings.HasPrefix(reply, "You don't have permission") {
+			t.Errorf("Expected %s to be refused, got %q", text, reply)
+		}
+	}
+	if mockLineClient.LastBroadcastRequest != nil {
+		t.Error("Expected no broadcast")
+	}
+	if prompt := service.currentSystemPrompt(); prompt != "You are a helpful assistant" {
+		t.Errorf("Expected system prompt unchanged, got %q", prompt)
+	}
+
+	help := sendCommand(t, service, mockLineClient, "/help")
+	adminHelp := sendAdminCommand(t, service, mockLineClient, "/help")
+	if strings.Contains(help, "/model") || strings.Contains(help, "/broadcast") {
+		t.Errorf("Expected admin commands hidden from help, got %q", help)
+	}
+	for _, usage := range []string{"/model", "/stats", "/broadcast <text>", "/prompt"} {
+		if !strings.Contains(adminHelp, usage) {
+			t.Errorf("Expected %s in admin help, got %q", usage, adminHelp)
+		}
+	}
+}
+
+// TestModelCommand_ListsModels tests that /model shows the current model and LM Studio's models
+func TestModelCommand_ListsModels(t *testing.T) {
+	mockLineClient := &MockLineClient{}
+	service := newAdminTestService(mockLineClient, &MockLMStudioClient{ListModelsFunc: testModels}, &countingSessionStore{})
+
+	reply := sendAdminCommand(t, service, mockLineClient, "/model")
+
+	expected := "Current model: qwen2.5-7b-instruct\nAvailable models:\n- qwen2.5-7b-instruct\n- llama-3.2-3b-instruct"
+	if reply != expected {
+		t.Errorf("Expected %q, got %q", expected, reply)
+	}
+}
+
+// TestModelCommand_Switches tests that /model <id> switches models used for later requests and /model reset restores it
+func TestModelCommand_Switches(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newAdminTestService(mockLineClient, &MockLMStudioClient{ListModelsFunc: testModels}, &countingSessionStore{})
+
+	// Act
+	switched := sendAdminCommand(t, service, mockLineClient, "/model llama-3.2-3b-instruct")
+	request := service.buildChatRequest("", "Hello", nil)
+	notFound := sendAdminCommand(t, service, mockLineClient, "/model gpt-4")
+	reset := sendAdminCommand(t, service, mockLineClient, "/model reset")
+
+	// Assert
+	if switched != "Model switched to llama-3.2-3b-instruct." {
+		t.Errorf("Unexpected reply: %q", switched)
+	}
+	if request.Model == nil || *request.Model != "llama-3.2-3b-instruct" {
+		t.Errorf("Expected requests to use the new model, got %v", request.Model)
+	}
+	if !strings.HasPrefix(notFound, "Model not found: gpt-4") {
+		t.Errorf("Unexpected reply: %q", notFound)
+	}
+	if reset != "Model reset to qwen2.5-7b-instruct." || service.currentModel() != "qwen2.5-7b-instruct" {
+		t.Errorf("Expected configured model restored, got %q (%s)", reset, service.currentModel())
+	}
+}
+
+// TestModelCommand_ListModelsError tests that LM Studio failures are reported without switching
+func TestModelCommand_ListModelsError(t *testing.T) {
+	mockLineClient := &MockLineClient{}
+	lmStudioClient := &MockLMStudioClient{
+		ListModelsFunc: func(ctx context.Context) ([]domain.ModelInfo, error) {
+			return nil, domain.ErrLMStudioUnavailable
+		},
+	}
+	service := newAdminTestService(mockLineClient, lmStudioClient, &countingSessionStore{})
+
+	reply := sendAdminCommand(t, service, mockLineClient, "/model llama-3.2-3b-instruct")
+
+	if !strings.HasPrefix(reply, "Couldn't list LM Studio models") {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if service.currentModel() != "qwen2.5-7b-instruct" {
+		t.Errorf("Expected model unchanged, got %s", service.currentModel())
+	}
+}
+
+// TestStatsCommand tests that /stats reports the session count, or why it can't
+func TestStatsCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    *countingSessionStore
+		expected string
+	}{
+		{"counted", &countingSessionStore{count: 3}, "Active sessions: 3\nModel: qwen2.5-7b-instruct"},
+		{"store failure", &countingSessionStore{err: errors.New("connection refused")}, "Couldn't count sessions right now. Please try again later."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLineClient := &MockLineClient{}
+			service := newAdminTestService(mockLineClient, &MockLMStudioClient{}, tt.store)
+
+			reply := sendAdminCommand(t, service, mockLineClient, "/stats")
+
+			if reply != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, reply)
+			}
+		})
+	}
+}
+
+// TestBroadcastCommand tests that /broadcast sends the text, line breaks included, to every friend
+func TestBroadcastCommand(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newAdminTestService(mockLineClient, &MockLMStudioClient{}, &countingSessionStore{})
+
+	// Act
+	reply := sendAdminCommand(t, service, mockLineClient, "/broadcast\nMaintenance tonight\nBack at 2am")
+
+	// Assert
+	request := mockLineClient.LastBroadcastRequest
+	if request == nil {
+		t.Fatal("Expected BroadcastMessage to be called")
+	}
+	if request.Messages[0].Text != "Maintenance tonight\nBack at 2am" {
+		t.Errorf("Unexpected broadcast text: %q", request.Messages[0].Text)
+	}
+	if reply != "Broadcast sent." {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if usage := sendAdminCommand(t, service, mockLineClient, "/broadcast"); usage != "Usage: /broadcast <text>" {
+		t.Errorf("Unexpected reply: %q", usage)
+	}
+}
+
+// TestPromptCommand tests that /prompt shows, replaces, and resets the system prompt
+func TestPromptCommand(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newAdminTestService(mockLineClient, &MockLMStudioClient{}, &countingSessionStore{})
+
+	// Act
+	updated := sendAdminCommand(t, service, mockLineClient, "/prompt You are a pirate.")
+	request := service.buildChatRequest("", "Hello", nil)
+	shown := sendAdminCommand(t, service, mockLineClient, "/prompt")
+	reset := sendAdminCommand(t, service, mockLineClient, "/prompt reset")
+
+	// Assert
+	if updated != "System prompt updated." {
+		t.Errorf("Unexpected reply: %q", updated)
+	}
+	if request.Messages[0].Content != "You are a pirate." {
+		t.Errorf("Expected requests to use the new prompt, got %q", request.Messages[0].Content)
+	}
+	if shown != "Current system prompt:\nYou are a pirate." {
+		t.Errorf("Unexpected reply: %q", shown)
+	}
+	if reset != "System prompt reset." || service.currentSystemPrompt() != "You are a helpful assistant" {
+		t.Errorf("Expected configured prompt restored, got %q (%s)", reset, service.currentSystemPrompt())
+	}
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+)
+
+// User-friendly message when a voice message can't be downloaded or transcribed
+const audioErrorMessage = "Sorry, I couldn't listen to that voice message. Please try again or type your message."
+
+// User-friendly message when a voice message is too large to download
+const audioTooLargeMessage = "Sorry, that voice message is too long for me to listen to."
+
+// User-friendly message when a voice message contains no recognizable speech
+const audioEmptyMessage = "Sorry, I couldn't hear anything in that voice message."
+
+// errEmptyTranscript indicates the transcriber returned no text
+var errEmptyTranscript = errors.New("empty transcript")
+
+// WithTranscriber func - Answers voice messages by transcribing them and replying to the transcript
+// Audio messages are ignored when no transcriber is configured.
+func WithTranscriber(transcriber output.Transcriber) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.transcriber = transcriber
+	}
+}
+
+// transcribeAudio - Helper method to download a voice message from LINE and convert it to text
+func (s *LineWebhookService) transcribeAudio(messageID string) (string, error) {
+	content, err := s.lineClient.GetMessageContent(messageID)
+	if err != nil {
+		return "", fmt.Errorf("failed to download audio %s: %w", messageID, err)
+	}
+
+	response, err := s.transcriber.Transcribe(context.Background(), domain.TranscriptionRequest{
+		Audio:       content.Data,
+		ContentType: content.ContentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to transcribe audio %s: %w", messageID, err)
+	}
+
+	if response.Text == "" {
+		return "", fmt.Errorf("failed to transcribe audio %s: %w", messageID, errEmptyTranscript)
+	}
+
+	return response.Text, nil
+}
+
+// audioUserMessage - Helper to choose the user-facing message for a voice message error
+func audioUserMessage(err error) string {
+	switch {
+	case errors.Is(err, errEmptyTranscript):
+		return audioEmptyMessage
+	case errors.Is(err, domain.ErrLineContentTooLarge):
+		return audioTooLargeMessage
+	default:
+		return audioErrorMessage
+	}
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// MockTranscriber implements output.Transcriber for testing
+type MockTranscriber struct {
+	TranscribeFunc func(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error)
+
+	// Captured values for assertions
+	LastRequest *domain.TranscriptionRequest
+}
+
+func (m *MockTranscriber) Transcribe(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error) {
+	m.LastRequest = &request
+	if m.TranscribeFunc != nil {
+		return m.TranscribeFunc(ctx, request)
+	}
+	return &domain.TranscriptionResponse{Text: "transcribed text"}, nil
+}
+
+// createAudioMessageEvent creates a voice message event from the test user
+func createAudioMessageEvent() domain.LineWebhookEvent {
+	event := createTextMessageEvent("")
+	event.Message.Type = domain.LineMessageTypeAudio
+	event.Message.Duration = 3000
+	return event
+}
+
+// TestAudioMessage_IgnoredWithoutTranscriber tests that voice messages are ignored when no transcriber is configured
+func TestAudioMessage_IgnoredWithoutTranscriber(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createAudioMessageEvent()}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest != nil {
+		t.Error("Expected LM Studio not to be called")
+	}
+	if mockLineClient.LastReplyRequest != nil {
+		t.Error("Expected no reply")
+	}
+}
+
+// TestAudioMessage_TranscriptAnsweredLikeText tests that the downloaded audio is transcribed and
+// the transcript is sent to LM Studio and stored as the user's message
+func TestAudioMessage_TranscriptAnsweredLikeText(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{
+		GetMessageContentFunc: func(messageID string) (*domain.LineMessageContent, error) {
+			return &domain.LineMessageContent{Data: []byte("voice-bytes"), ContentType: "audio/m4a"}, nil
+		},
+	}
+	mockTranscriber := &MockTranscriber{
+		TranscribeFunc: func(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error) {
+			return &domain.TranscriptionResponse{Text: "What's the weather like?"}, nil
+		},
+	}
+	mockLMStudioClient := &MockLMStudioClient{}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithTranscriber(mockTranscriber))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createAudioMessageEvent()}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if string(mockTranscriber.LastRequest.Audio) != "voice-bytes" || mockTranscriber.LastRequest.ContentType != "audio/m4a" {
+		t.Errorf("Expected downloaded audio to be transcribed, got %+v", mockTranscriber.LastRequest)
+	}
+
+	messages := mockLMStudioClient.LastChatRequest.Messages
+	if last := messages[len(messages)-1]; last.Content != "What's the weather like?" {
+		t.Errorf("Expected transcript as user message, got %q", last.Content)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "AI response" {
+		t.Errorf("Expected AI reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+	if history := mockSessionStore.LastUpdatedSession.GetHistory(); history[0].Content != "What's the weather like?" {
+		t.Errorf("Expected transcript stored in history, got %q", history[0].Content)
+	}
+}
+
+// TestAudioMessage_TranscriptionErrorReplies tests that transcription failures and empty transcripts
+// get a friendly reply without calling LM Studio
+func TestAudioMessage_TranscriptionErrorReplies(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *domain.TranscriptionResponse
+		err      error
+		expected string
+	}{
+		{"service unavailable", nil, fmt.Errorf("%w: connection refused", domain.ErrTranscriptionUnavailable), audioErrorMessage},
+		{"empty transcript", &domain.TranscriptionResponse{Text: ""}, nil, audioEmptyMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockLineClient := &MockLineClient{}
+			mockLMStudioClient := &MockLMStudioClient{}
+			mockTranscriber := &MockTranscriber{
+				TranscribeFunc: func(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error) {
+					return tt.response, tt.err
+				},
+			}
+			service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+				WithTranscriber(mockTranscriber))
+
+			// Act
+			err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createAudioMessageEvent()}})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if mockLMStudioClient.LastChatRequest != nil {
+				t.Error("Expected LM Studio not to be called")
+			}
+			if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != tt.expected {
+				t.Errorf("Expected reply %q, got %+v", tt.expected, mockLineClient.LastReplyRequest)
+			}
+		})
+	}
+}
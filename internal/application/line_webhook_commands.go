@@ -0,0 +1,222 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Prefix that marks a text message as a command
+const commandPrefix = "/"
+
+// CommandContext struct - A command invocation passed to permission checks and handlers
+type CommandContext struct {
+	Event      domain.LineWebhookEvent
+	SessionKey string   // Conversation the command was sent in: the user ID in 1:1 chats, otherwise the group or room ID
+	UserID     string   // Sender, empty when LINE did not share it
+	Name       string   // Name or alias the command was invoked with, lowercased and without the prefix
+	RawArgs    string   // Text after the command name, trimmed
+	Args       []string // RawArgs split by the command's ParseArgs
+}
+
+// CommandHandler func - Runs a command and returns the reply messages
+type CommandHandler func(cmd CommandContext) []domain.LineOutgoingMessage
+
+// CommandPermission func - Reports whether the invocation may run the command
+type CommandPermission func(cmd CommandContext) bool
+
+// Command struct - A slash command answered without LM Studio
+type Command struct {
+	Name        string   // e.g. "echo" for /echo
+	Aliases     []string // Other names the command answers to
+	Usage       string   // e.g. "/echo <text>", shown in /help and when arguments are missing
+	Description string   // One line shown in /help
+	MinArgs     int      // Fewer arguments reply with the usage instead of running the handler
+	// ParseArgs splits RawArgs into Args; an error replies with the usage. Defaults to strings.Fields.
+	ParseArgs  func(rawArgs string) ([]string, error)
+	Permission CommandPermission // Nil allows everyone; denied commands are hidden from /help
+	Handler    CommandHandler
+}
+
+// CommandRegistry struct - Commands by name and alias, in registration order
+type CommandRegistry struct {
+	byName   map[string]*Command
+	commands []*Command
+}
+
+// NewCommandRegistry func - Creates an empty command registry
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		byName: make(map[string]*Command),
+	}
+}
+
+// Register func - Adds commands to the registry
+// Names and aliases are case-insensitive. Returns an error if a command has no name or handler,
+// or one of its names is already taken.
+func (r *CommandRegistry) Register(commands ...Command) error {
+	for _, command := range commands {
+		if command.Name == "" || command.Handler == nil {
+			return fmt.Errorf("command %q must have a name and a handler", command.Name)
+		}
+
+		cmd := command
+		names := append([]string{cmd.Name}, cmd.Aliases...)
+		for _, name := range names {
+			if _, exists := r.byName[strings.ToLower(name)]; exists {
+				return fmt.Errorf("command name %q is already registered", name)
+			}
+		}
+		for _, name := range names {
+			r.byName[strings.ToLower(name)] = &cmd
+		}
+		r.commands = append(r.commands, &cmd)
+	}
+	return nil
+}
+
+// Lookup func - Finds a command by name or alias
+func (r *CommandRegistry) Lookup(name string) (*Command, bool) {
+	command, ok := r.byName[strings.ToLower(name)]
+	return command, ok
+}
+
+// Commands func - Returns the registered commands in registration order
+func (r *CommandRegistry) Commands() []*Command {
+	return r.commands
+}
+
+// WithCommands func - Registers commands alongside the built-in ones
+// A command whose name or alias is already taken is logged and skipped.
+func WithCommands(commands ...Command) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		for _, command := range commands {
+			if err := s.commands.Register(command); err != nil {
+				logrus.Errorf("Failed to register command /%s: %v", command.Name, err)
+			}
+		}
+	}
+}
+
+// handleCommand - Business logic for command processing
+// Finds the command in the registry, checks its permission and arguments, and runs its handler.
+func (s *LineWebhookService) handleCommand(event domain.LineWebhookEvent, text string) []domain.LineOutgoingMessage {
+	text = strings.TrimSpace(strings.TrimPrefix(text, commandPrefix))
+	name, rawArgs := text, ""
+	// The name ends at the first space or line break, so arguments may span several lines
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		name, rawArgs = text[:i], text[i:]
+	}
+	name = strings.ToLower(name)
+
+	command, ok := s.commands.Lookup(name)
+	if !ok {
+		return textMessages(fmt.Sprintf("Unknown command: %s%s\nType /help for available commands", commandPrefix, name))
+	}
+
+	cmd := CommandContext{
+		Event:      event,
+		SessionKey: conversationKey(event.Source),
+		UserID:     event.Source.UserID,
+		Name:       name,
+		RawArgs:    strings.TrimSpace(rawArgs),
+	}
+
+	if command.Permission != nil && !command.Permission(cmd) {
+		return textMessages(fmt.Sprintf("You don't have permission to use %s%s.", commandPrefix, command.Name))
+	}
+
+	parseArgs := command.ParseArgs
+	if parseArgs == nil {
+		parseArgs = func(rawArgs string) ([]string, error) { return strings.Fields(rawArgs), nil }
+	}
+	args, err := parseArgs(cmd.RawArgs)
+	if err != nil || len(args) < command.MinArgs {
+		return textMessages("Usage: " + commandUsage(command))
+	}
+	cmd.Args = args
+
+	return command.Handler(cmd)
+}
+
+// builtinCommands - Helper method to create the commands every service answers
+// Commands of optional features, such as /todo and /persona, are registered by the options that enable them.
+func (s *LineWebhookService) builtinCommands() []Command {
+	return []Command{
+		{
+			Name:        "help",
+			Description: "Show this message",
+			Handler:     s.helpCommand,
+		},
+		{
+			Name:        "about",
+			Description: "About this bot",
+			Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+				return textMessages("LINE Bot powered by Go + Fiber\nBuilt with Hexagonal Architecture")
+			},
+		},
+		{
+			Name:        "echo",
+			Usage:       "/echo <text>",
+			Description: "Echo your message",
+			MinArgs:     1,
+			Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+				return textMessages(strings.Join(cmd.Args, " "))
+			},
+		},
+		{
+			Name:        "clear",
+			Description: "Clear conversation history",
+			Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+				s.clearHistory(cmd.SessionKey)
+				return textMessages("Conversation history cleared.")
+			},
+		},
+	}
+}
+
+// clearHistory - Helper method to clear the conversation history, keeping the chosen persona
+func (s *LineWebhookService) clearHistory(sessionKey string) {
+	if s.sessionStore == nil {
+		return
+	}
+
+	session, _ := s.sessionStore.GetSession(sessionKey)
+	if session != nil && session.Persona != "" {
+		// Start a fresh session that remembers the persona
+		cleared := domain.NewConversationSession(sessionKey, s.sessionTimeout, s.sessionMaxTurns)
+		cleared.Persona = session.Persona
+		if err := s.sessionStore.UpdateSession(cleared); err != nil {
+			logrus.Warnf("Failed to clear session for %s: %v", sessionKey, err)
+		}
+		return
+	}
+
+	// Clear conversation history by deleting the conversation's session
+	_ = s.sessionStore.DeleteSession(sessionKey)
+}
+
+// helpCommand - Helper method to list the commands the sender may use
+func (s *LineWebhookService) helpCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	var builder strings.Builder
+	builder.WriteString("Available commands:")
+	for _, command := range s.commands.Commands() {
+		if command.Permission != nil && !command.Permission(cmd) {
+			continue
+		}
+		fmt.Fprintf(&builder, "\n%s - %s", commandUsage(command), command.Description)
+	}
+	return textMessages(builder.String())
+}
+
+// commandUsage - Helper to get a command's usage, defaulting to its name
+func commandUsage(command *Command) string {
+	if command.Usage != "" {
+		return command.Usage
+	}
+	return commandPrefix + command.Name
+}
@@ -0,0 +1,229 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// newCommandTestService creates a service with the given options for command tests
+func newCommandTestService(lineClient *MockLineClient, opts ...LineWebhookServiceOption) *LineWebhookService {
+	return NewLineWebhookService(lineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, opts...)
+}
+
+// sendCommand sends text from the test user and returns the reply text
+func sendCommand(t *testing.T, service *LineWebhookService, lineClient *MockLineClient, text string) string {
+	t.Helper()
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent(text)}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if lineClient.LastReplyRequest == nil {
+		t.Fatal("Expected a reply")
+	}
+	return lineClient.LastReplyRequest.Messages[0].Text
+}
+
+// TestCommandRegistry_Register tests that names and aliases are case-insensitive and must be unique
+func TestCommandRegistry_Register(t *testing.T) {
+	// Arrange
+	handler := func(cmd CommandContext) []domain.LineOutgoingMessage { return nil }
+	registry := NewCommandRegistry()
+
+	// Act
+	err := registry.Register(Command{Name: "Ping", Aliases: []string{"p"}, Handler: handler})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, name := range []string{"ping", "PING", "P"} {
+		if command, ok := registry.Lookup(name); !ok || command.Name != "Ping" {
+			t.Errorf("Expected %q to find /Ping", name)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		command Command
+	}{
+		{"duplicate name", Command{Name: "ping", Handler: handler}},
+		{"alias taken", Command{Name: "pong", Aliases: []string{"P"}, Handler: handler}},
+		{"missing name", Command{Handler: handler}},
+		{"missing handler", Command{Name: "pong"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := registry.Register(tt.command); err == nil {
+				t.Error("Expected an error")
+			}
+			if len(registry.Commands()) != 1 {
+				t.Errorf("Expected the command not to be registered, got %d commands", len(registry.Commands()))
+			}
+		})
+	}
+}
+
+// TestCommands_CustomCommand tests that registered commands run with their alias and parsed arguments
+func TestCommands_CustomCommand(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	var received CommandContext
+	service := newCommandTestService(mockLineClient, WithCommands(Command{
+		Name:        "roll",
+		Aliases:     []string{"dice"},
+		Usage:       "/roll <sides>",
+		Description: "Roll a die",
+		MinArgs:     1,
+		Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+			received = cmd
+			return textMessages("Rolled " + cmd.Args[0])
+		},
+	}))
+
+	// Act
+	reply := sendCommand(t, service, mockLineClient, "/DICE 6  extra")
+
+	// Assert
+	if reply != "Rolled 6" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if received.Name != "dice" || received.RawArgs != "6  extra" || len(received.Args) != 2 {
+		t.Errorf("Unexpected command context: %+v", received)
+	}
+	if received.UserID != "test-user-id" || received.SessionKey != "test-user-id" {
+		t.Errorf("Expected sender and session of the test user, got %+v", received)
+	}
+}
+
+// TestCommands_Usage tests that missing or unparsable arguments reply with the command's usage
+func TestCommands_Usage(t *testing.T) {
+	called := false
+	handler := func(cmd CommandContext) []domain.LineOutgoingMessage {
+		called = true
+		return textMessages("ran")
+	}
+	parseNumber := func(rawArgs string) ([]string, error) {
+		if strings.Trim(rawArgs, "0123456789") != "" {
+			return nil, errors.New("not a number")
+		}
+		return []string{rawArgs}, nil
+	}
+
+	tests := []struct {
+		name     string
+		command  Command
+		text     string
+		expected string
+	}{
+		{"too few arguments", Command{Name: "pair", Usage: "/pair <a> <b>", MinArgs: 2, Handler: handler}, "/pair one", "Usage: /pair <a> <b>"},
+		{"parse error", Command{Name: "wait", Usage: "/wait <seconds>", ParseArgs: parseNumber, Handler: handler}, "/wait soon", "Usage: /wait <seconds>"},
+		{"usage defaults to name", Command{Name: "pair", MinArgs: 1, Handler: handler}, "/pair", "Usage: /pair"},
+		{"built-in echo", Command{Name: "noop", Handler: handler}, "/echo", "Usage: /echo <text>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called = false
+			mockLineClient := &MockLineClient{}
+			service := newCommandTestService(mockLineClient, WithCommands(tt.command))
+
+			// Act
+			reply := sendCommand(t, service, mockLineClient, tt.text)
+
+			// Assert
+			if called {
+				t.Error("Expected the handler not to run")
+			}
+			if reply != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, reply)
+			}
+		})
+	}
+}
+
+// TestCommands_Permission tests that denied commands are refused and hidden from /help
+func TestCommands_Permission(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	onlyAdmin := func(cmd CommandContext) bool { return cmd.UserID == "admin-user-id" }
+	service := newCommandTestService(mockLineClient, WithCommands(Command{
+		Name:        "secret",
+		Description: "Admins only",
+		Permission:  onlyAdmin,
+		Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+			return textMessages("secret")
+		},
+	}))
+
+	// Act
+	reply := sendCommand(t, service, mockLineClient, "/secret")
+	help := sendCommand(t, service, mockLineClient, "/help")
+
+	event := createTextMessageEvent("/help")
+	event.Source.UserID = "admin-user-id"
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	adminHelp := mockLineClient.LastReplyRequest.Messages[0].Text
+
+	// Assert
+	if reply != "You don't have permission to use /secret." {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if strings.Contains(help, "/secret") {
+		t.Errorf("Expected /secret hidden from help, got %q", help)
+	}
+	if !strings.Contains(adminHelp, "/secret - Admins only") {
+		t.Errorf("Expected /secret in admin help, got %q", adminHelp)
+	}
+}
+
+// TestCommands_HelpIsGenerated tests that /help lists built-in and registered commands in order
+func TestCommands_HelpIsGenerated(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newCommandTestService(mockLineClient, WithCommands(
+		Command{Name: "roll", Usage: "/roll <sides>", Description: "Roll a die", Handler: func(cmd CommandContext) []domain.LineOutgoingMessage { return nil }},
+		// Built-in commands cannot be replaced
+		Command{Name: "help", Description: "Replaced help", Handler: func(cmd CommandContext) []domain.LineOutgoingMessage { return nil }},
+	))
+
+	// Act
+	reply := sendCommand(t, service, mockLineClient, "/help")
+
+	// Assert
+	expected := "Available commands:\n/help - Show this message\n/about - About this bot\n/echo <text> - Echo your message\n/clear - Clear conversation history\n/roll <sides> - Roll a die"
+	if reply != expected {
+		t.Errorf("Expected %q, got %q", expected, reply)
+	}
+}
+
+// TestCommands_HelpListsConfiguredFeatures tests that /todo and /persona are only offered once enabled
+func TestCommands_HelpListsConfiguredFeatures(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newCommandTestService(mockLineClient, WithTodoService(&MockTodoService{}), WithPersonas(testPersonas()))
+
+	// Act
+	reply := sendCommand(t, service, mockLineClient, "/help")
+
+	// Assert
+	if !strings.HasSuffix(reply, "\n/todo - Manage your todos\n/persona - Choose how the bot talks") {
+		t.Errorf("Expected /todo and /persona in help, got %q", reply)
+	}
+}
+
+// TestCommands_Unknown tests that unregistered commands point to /help
+func TestCommands_Unknown(t *testing.T) {
+	mockLineClient := &MockLineClient{}
+	service := newCommandTestService(mockLineClient)
+
+	reply := sendCommand(t, service, mockLineClient, "/Nope now")
+
+	if reply != "Unknown command: /nope\nType /help for available commands" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
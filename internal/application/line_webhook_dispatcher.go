@@ -0,0 +1,158 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/input"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Compile-time check to ensure LineWebhookDispatcher implements LineWebhookService interface
+var _ input.LineWebhookService = (*LineWebhookDispatcher)(nil)
+
+// LineWebhookDispatcher struct - Asynchronous decorator for the LINE webhook use case
+// Events are queued and processed by a bounded pool of workers so the webhook can be
+// acknowledged immediately. Each conversation (user, group, or room) is always routed to
+// the same worker, which preserves the order of a user's turns.
+type LineWebhookDispatcher struct {
+	service input.LineWebhookService
+	queues  []chan domain.LineWebhookEvent
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewLineWebhookDispatcher func - Creates a dispatcher and starts its workers
+// workers: Number of concurrent workers processing events
+// queueSize: Number of events each worker can hold before HandleWebhook reports the queue as full
+func NewLineWebhookDispatcher(service input.LineWebhookService, workers, queueSize int) *LineWebhookDispatcher {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+
+	d := &LineWebhookDispatcher{
+		service: service,
+		queues:  make([]chan domain.LineWebhookEvent, workers),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan domain.LineWebhookEvent, queueSize)
+		d.wg.Add(1)
+		go d.worker(i, d.queues[i])
+	}
+
+	logrus.Infof("LINE webhook dispatcher started with %d workers, queue size %d per worker", workers, queueSize)
+
+	return d
+}
+
+// HandleWebhook func - Enqueues webhook events for asynchronous processing
+// Returns ErrWebhookQueueFull if a worker queue has no capacity left and
+// ErrWebhookDispatcherClosed once Shutdown has been called.
+func (d *LineWebhookDispatcher) HandleWebhook(request domain.LineWebhookRequest) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return domain.ErrWebhookDispatcherClosed
+	}
+
+	for _, event := range request.Events {
+		queue := d.queues[d.queueIndex(event)]
+		select {
+		case queue <- event:
+		default:
+			return fmt.Errorf("%w: dropping event type=%s for userID=%s", domain.ErrWebhookQueueFull, event.Type, event.Source.UserID)
+		}
+	}
+
+	return nil
+}
+
+// Shutdown func - Stops accepting events and waits for queued events to be processed
+// Returns the context error if the queue is not drained before the context is done.
+func (d *LineWebhookDispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logrus.Info("LINE webhook dispatcher drained")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to drain webhook queue: %w", ctx.Err())
+	}
+}
+
+// worker - Processes events from a single queue in order
+func (d *LineWebhookDispatcher) worker(id int, queue <-chan domain.LineWebhookEvent) {
+	defer d.wg.Done()
+
+	for event := range queue {
+		d.process(id, event)
+	}
+}
+
+// process - Handles a single event, recovering from panics so the worker stays alive
+func (d *LineWebhookDispatcher) process(id int, event domain.LineWebhookEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("Webhook worker %d recovered from panic: %v", id, r)
+		}
+	}()
+
+	request := domain.LineWebhookRequest{
+		Events: []domain.LineWebhookEvent{event},
+	}
+
+	if err := d.service.HandleWebhook(request); err != nil {
+		logrus.Errorf("Webhook worker %d failed to handle event: %v", id, err)
+	}
+}
+
+// queueIndex - Selects the worker queue for an event based on its conversation source
+func (d *LineWebhookDispatcher) queueIndex(event domain.LineWebhookEvent) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(conversationKey(event.Source)))
+	return int(h.Sum32() % uint32(len(d.queues)))
+}
+
+// conversationKey - Returns the identifier of the conversation an event belongs to
+// This is the group or room ID for group chats and the user ID for 1:1 chats. It is used
+// as the session key and as the push message destination.
+func conversationKey(source domain.LineSource) string {
+	switch {
+	case source.GroupID != "":
+		return source.GroupID
+	case source.RoomID != "":
+		return source.RoomID
+	default:
+		return source.UserID
+	}
+}
+
+// isGroupConversation - Reports whether an event comes from a group or multi-person room
+func isGroupConversation(source domain.LineSource) bool {
+	return source.GroupID != "" || source.RoomID != ""
+}
@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-template/internal/domain"
+)
+
+// RecordingWebhookService implements input.LineWebhookService and records handled events
+type RecordingWebhookService struct {
+	mu     sync.Mutex
+	events []domain.LineWebhookEvent
+
+	// Optional hook invoked for every event
+	HandleFunc func(event domain.LineWebhookEvent)
+}
+
+func (r *RecordingWebhookService) HandleWebhook(request domain.LineWebhookRequest) error {
+	for _, event := range request.Events {
+		if r.HandleFunc != nil {
+			r.HandleFunc(event)
+		}
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+func (r *RecordingWebhookService) Events() []domain.LineWebhookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.LineWebhookEvent(nil), r.events...)
+}
+
+// userTextEvent creates a text message event for the given user
+func userTextEvent(userID, text string) domain.LineWebhookEvent {
+	event := createTextMessageEvent(text)
+	event.Source.UserID = userID
+	return event
+}
+
+// TestDispatcher_PreservesPerUserOrder tests that each user's events are processed in the order received
+func TestDispatcher_PreservesPerUserOrder(t *testing.T) {
+	// Arrange
+	recorder := &RecordingWebhookService{
+		HandleFunc: func(event domain.LineWebhookEvent) {
+			time.Sleep(100 * time.Microsecond)
+		},
+	}
+	dispatcher := NewLineWebhookDispatcher(recorder, 4, 100)
+
+	users := []string{"user-a", "user-b", "user-c", "user-d", "user-e"}
+	const perUser = 20
+
+	// Act
+	for i := 0; i < perUser; i++ {
+		var events []domain.LineWebhookEvent
+		for _, user := range users {
+			events = append(events, userTextEvent(user, fmt.Sprintf("%d", i)))
+		}
+		if err := dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: events}); err != nil {
+			t.Fatalf("Expected no error enqueuing events, got: %v", err)
+		}
+	}
+
+	if err := dispatcher.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got: %v", err)
+	}
+
+	// Assert
+	next := make(map[string]int)
+	for _, event := range recorder.Events() {
+		want := fmt.Sprintf("%d", next[event.Source.UserID])
+		if event.Message.Text != want {
+			t.Errorf("User %s: expected event %s, got %s", event.Source.UserID, want, event.Message.Text)
+		}
+		next[event.Source.UserID]++
+	}
+	for _, user := range users {
+		if next[user] != perUser {
+			t.Errorf("User %s: expected %d events processed, got %d", user, perUser, next[user])
+		}
+	}
+}
+
+// TestDispatcher_HandleWebhookReturnsImmediately tests that enqueuing does not wait for processing
+func TestDispatcher_HandleWebhookReturnsImmediately(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	recorder := &RecordingWebhookService{
+		HandleFunc: func(event domain.LineWebhookEvent) {
+			<-release
+		},
+	}
+	dispatcher := NewLineWebhookDispatcher(recorder, 1, 10)
+
+	// Act
+	start := time.Now()
+	err := dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "hi")}})
+	elapsed := time.Since(start)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if elapsed > 100*time.Millisecond {
+		t.Errorf("Expected HandleWebhook to return immediately, took %v", elapsed)
+	}
+
+	close(release)
+	if err := dispatcher.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got: %v", err)
+	}
+	if len(recorder.Events()) != 1 {
+		t.Errorf("Expected queued event to be processed during shutdown, got %d", len(recorder.Events()))
+	}
+}
+
+// TestDispatcher_QueueFull tests that a full queue is reported with ErrWebhookQueueFull
+func TestDispatcher_QueueFull(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	recorder := &RecordingWebhookService{
+		HandleFunc: func(event domain.LineWebhookEvent) {
+			started <- struct{}{}
+			<-release
+		},
+	}
+	dispatcher := NewLineWebhookDispatcher(recorder, 1, 1)
+	defer func() {
+		close(release)
+		_ = dispatcher.Shutdown(context.Background())
+	}()
+
+	// First event occupies the worker, second fills the queue
+	_ = dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "1")}})
+	<-started
+	_ = dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "2")}})
+
+	// Act
+	err := dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "3")}})
+
+	// Assert
+	if !errors.Is(err, domain.ErrWebhookQueueFull) {
+		t.Errorf("Expected ErrWebhookQueueFull, got: %v", err)
+	}
+}
+
+// TestDispatcher_RejectsAfterShutdown tests that events are rejected once the dispatcher is closed
+func TestDispatcher_RejectsAfterShutdown(t *testing.T) {
+	// Arrange
+	dispatcher := NewLineWebhookDispatcher(&RecordingWebhookService{}, 2, 10)
+	if err := dispatcher.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got: %v", err)
+	}
+
+	// Act
+	err := dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "hi")}})
+
+	// Assert
+	if !errors.Is(err, domain.ErrWebhookDispatcherClosed) {
+		t.Errorf("Expected ErrWebhookDispatcherClosed, got: %v", err)
+	}
+
+	// Shutdown is idempotent
+	if err := dispatcher.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected second Shutdown to succeed, got: %v", err)
+	}
+}
+
+// TestDispatcher_ShutdownTimeout tests that Shutdown returns when the context expires before draining
+func TestDispatcher_ShutdownTimeout(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	recorder := &RecordingWebhookService{
+		HandleFunc: func(event domain.LineWebhookEvent) {
+			<-release
+		},
+	}
+	dispatcher := NewLineWebhookDispatcher(recorder, 1, 10)
+	defer close(release)
+	_ = dispatcher.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{userTextEvent("user-a", "hi")}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Act
+	err := dispatcher.Shutdown(ctx)
+
+	// Assert
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+}
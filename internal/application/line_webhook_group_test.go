@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// createGroupTextMessageEvent creates a text message event from a member of a LINE group
+func createGroupTextMessageEvent(text string, mentionsBot bool) domain.LineWebhookEvent {
+	event := createTextMessageEvent(text)
+	event.Source = domain.LineSource{
+		Type:    domain.LineSourceTypeGroup,
+		UserID:  "test-user-id",
+		GroupID: "test-group-id",
+	}
+	event.Message.MentionsBot = mentionsBot
+	return event
+}
+
+// TestGroup_IgnoresMessagesWithoutMention tests that the bot stays quiet in groups unless mentioned
+func TestGroup_IgnoresMessagesWithoutMention(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createGroupTextMessageEvent("what's for lunch?", false)}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest != nil {
+		t.Error("Expected no LM Studio call for a group message without mention")
+	}
+	if mockLineClient.LastReplyRequest != nil {
+		t.Error("Expected no reply for a group message without mention")
+	}
+}
+
+// TestGroup_MentionedMessageUsesGroupSession tests that a mentioned message is answered and
+// stored in the group's session rather than the sender's
+func TestGroup_MentionedMessageUsesGroupSession(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createGroupTextMessageEvent("what's for lunch?", true)}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest == nil {
+		t.Fatal("Expected LM Studio to be called for a mentioned message")
+	}
+	if mockSessionStore.LastGetUserID != "test-group-id" {
+		t.Errorf("Expected session lookup by group ID, got %q", mockSessionStore.LastGetUserID)
+	}
+	if mockSessionStore.LastUpdatedSession == nil || mockSessionStore.LastUpdatedSession.UserID != "test-group-id" {
+		t.Errorf("Expected turn to be stored in the group session, got %+v", mockSessionStore.LastUpdatedSession)
+	}
+	if mockLineClient.LastReplyRequest == nil {
+		t.Error("Expected a reply to the mentioned message")
+	}
+}
+
+// TestGroup_CommandWithoutMentionIsHandled tests that commands work in groups without a mention
+// and act on the group's session
+func TestGroup_CommandWithoutMentionIsHandled(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createGroupTextMessageEvent("/clear", false)}})
+
+	// Assert
+	if len(mockSessionStore.DeleteCalls) != 1 || mockSessionStore.DeleteCalls[0] != "test-group-id" {
+		t.Errorf("Expected group session to be cleared, got %v", mockSessionStore.DeleteCalls)
+	}
+	if mockLineClient.LastReplyRequest == nil {
+		t.Error("Expected a reply to the command")
+	}
+}
+
+// TestRoom_LongReplyPushedToRoom tests that follow-up push messages go to the room, not the sender
+func TestRoom_LongReplyPushedToRoom(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			return &domain.ChatCompletionResponse{Content: strings.Repeat("A long answer. ", 500)}, nil
+		},
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	event := createTextMessageEvent("tell me everything")
+	event.Source = domain.LineSource{Type: domain.LineSourceTypeRoom, UserID: "test-user-id", RoomID: "test-room-id"}
+	event.Message.MentionsBot = true
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}})
+
+	// Assert
+	if len(mockLineClient.PushRequests) == 0 {
+		t.Fatal("Expected follow-up push messages for a long reply")
+	}
+	for _, push := range mockLineClient.PushRequests {
+		if push.To != "test-room-id" {
+			t.Errorf("Expected push to the room, got %q", push.To)
+		}
+	}
+}
+
+// TestGroup_StreamedReplyPushedToGroup tests that streamed segments after the first go to the group
+func TestGroup_StreamedReplyPushedToGroup(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return streamOf(
+				domain.ChatCompletionChunk{Content: "First sentence. "},
+				domain.ChatCompletionChunk{Content: strings.Repeat("More detail here. ", 15)},
+				domain.ChatCompletionChunk{Done: true},
+			), nil
+		},
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithStreaming(true))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createGroupTextMessageEvent("explain", true)}})
+
+	// Assert
+	if len(mockLineClient.PushRequests) == 0 {
+		t.Fatal("Expected streamed segments to be pushed")
+	}
+	if mockLineClient.PushRequests[0].To != "test-group-id" {
+		t.Errorf("Expected push to the group, got %q", mockLineClient.PushRequests[0].To)
+	}
+}
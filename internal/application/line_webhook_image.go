@@ -0,0 +1,65 @@
+package application
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang-template/internal/domain"
+)
+
+// Instruction sent alongside an image, since LINE image messages carry no caption
+const imagePrompt = "The user sent this image. Describe what it shows and respond helpfully."
+
+// Stand-in for the image in conversation history; images are not kept in the session
+const imageHistoryText = "[The user sent an image]"
+
+// User-friendly message when an image can't be downloaded
+const imageErrorMessage = "Sorry, I couldn't open that image. Please try sending it again."
+
+// User-friendly message when an image is larger than the bot downloads
+const imageTooLargeMessage = "Sorry, that image is too large for me to look at."
+
+// WithVision func - Answers image messages by sending them to a vision-capable model
+// Images are ignored when disabled, since text-only models reject image content.
+func WithVision(enabled bool) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.vision = enabled
+	}
+}
+
+// buildImageChatRequest - Helper method to build a ChatCompletionRequest asking about an image message
+// The image is downloaded from LINE and embedded as a base64 data URL image_url part. The token
+// budget only accounts for the text prompt; the image's cost depends on the model.
+func (s *LineWebhookService) buildImageChatRequest(messageID, persona string, history []domain.ChatMessage) (domain.ChatCompletionRequest, error) {
+	content, err := s.lineClient.GetMessageContent(messageID)
+	if err != nil {
+		return domain.ChatCompletionRequest{}, fmt.Errorf("failed to download image %s: %w", messageID, err)
+	}
+
+	contentType := content.ContentType
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = "image/jpeg" // LINE delivers images as JPEG
+	}
+	dataURL := fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(content.Data))
+
+	request := s.buildChatRequest(persona, imagePrompt, history)
+
+	// The user message is last; attach the image alongside the prompt
+	userMessage := &request.Messages[len(request.Messages)-1]
+	userMessage.Parts = []domain.ChatContentPart{
+		{Type: domain.ChatContentPartTypeText, Text: userMessage.Content},
+		{Type: domain.ChatContentPartTypeImageURL, ImageURL: dataURL},
+	}
+
+	return request, nil
+}
+
+// imageUserMessage - Helper to choose the user-facing message for an image download error
+func imageUserMessage(err error) string {
+	if errors.Is(err, domain.ErrLineContentTooLarge) {
+		return imageTooLargeMessage
+	}
+	return imageErrorMessage
+}
@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// createImageMessageEvent creates an image message event from the test user
+func createImageMessageEvent() domain.LineWebhookEvent {
+	event := createTextMessageEvent("")
+	event.Message.Type = domain.LineMessageTypeImage
+	return event
+}
+
+// TestImageMessage_IgnoredWithoutVision tests that image messages are ignored unless vision is enabled
+func TestImageMessage_IgnoredWithoutVision(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createImageMessageEvent()}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest != nil {
+		t.Error("Expected LM Studio not to be called")
+	}
+	if mockLineClient.LastReplyRequest != nil {
+		t.Error("Expected no reply")
+	}
+}
+
+// TestImageMessage_SentAsImageURLPart tests that the downloaded image is sent as a base64 data URL
+// next to the image prompt, and the turn is stored with a text placeholder
+func TestImageMessage_SentAsImageURLPart(t *testing.T) {
+	// Arrange
+	var downloadedID string
+	mockLineClient := &MockLineClient{
+		GetMessageContentFunc: func(messageID string) (*domain.LineMessageContent, error) {
+			downloadedID = messageID
+			return &domain.LineMessageContent{Data: []byte("png-bytes"), ContentType: "image/png"}, nil
+		},
+	}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			return &domain.ChatCompletionResponse{Content: "A cat on a sofa."}, nil
+		},
+	}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithVision(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createImageMessageEvent()}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if downloadedID != "test-message-id" {
+		t.Errorf("Expected content of test-message-id to be downloaded, got %q", downloadedID)
+	}
+
+	messages := mockLMStudioClient.LastChatRequest.Messages
+	parts := messages[len(messages)-1].Parts
+	if len(parts) != 2 {
+		t.Fatalf("Expected text and image parts, got %+v", parts)
+	}
+	if parts[0].Type != domain.ChatContentPartTypeText || parts[0].Text != imagePrompt {
+		t.Errorf("Expected image prompt text part, got %+v", parts[0])
+	}
+	expectedURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+	if parts[1].Type != domain.ChatContentPartTypeImageURL || parts[1].ImageURL != expectedURL {
+		t.Errorf("Expected image_url part %q, got %+v", expectedURL, parts[1])
+	}
+
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "A cat on a sofa." {
+		t.Errorf("Expected AI reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+
+	history := mockSessionStore.LastUpdatedSession.GetHistory()
+	if history[0].Content != imageHistoryText || len(history[0].Parts) != 0 {
+		t.Errorf("Expected image placeholder in history without parts, got %+v", history[0])
+	}
+}
+
+// TestImageMessage_TooLargeRepliesWithoutCallingLMStudio tests that oversized images get a
+// friendly reply and are not sent to LM Studio
+func TestImageMessage_TooLargeRepliesWithoutCallingLMStudio(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{
+		GetMessageContentFunc: func(messageID string) (*domain.LineMessageContent, error) {
+			return nil, fmt.Errorf("%w: 20000000 bytes", domain.ErrLineContentTooLarge)
+		},
+	}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithVision(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createImageMessageEvent()}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest != nil {
+		t.Error("Expected LM Studio not to be called")
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != imageTooLargeMessage {
+		t.Errorf("Expected too-large reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
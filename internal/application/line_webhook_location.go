@@ -0,0 +1,33 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang-template/internal/domain"
+)
+
+// Instruction appended to a shared location so the model can answer "what's near me" questions
+const locationPrompt = "Briefly acknowledge where I am and offer to help with things nearby. Use this location for later questions about places near me."
+
+// formatLocationMessage - Helper to describe a shared location as the user's message
+// The description is stored in history like a typed message, so follow-up questions keep the location.
+func formatLocationMessage(message *domain.LineMessage) string {
+	var b strings.Builder
+	b.WriteString("I'm sharing my location.\n")
+
+	if message.Title != "" {
+		fmt.Fprintf(&b, "Place: %s\n", message.Title)
+	}
+	if message.Address != "" {
+		fmt.Fprintf(&b, "Address: %s\n", message.Address)
+	}
+	fmt.Fprintf(&b, "Coordinates: %s, %s\n\n",
+		strconv.FormatFloat(message.Latitude, 'f', 6, 64),
+		strconv.FormatFloat(message.Longitude, 'f', 6, 64))
+
+	b.WriteString(locationPrompt)
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// createLocationMessageEvent creates a location message event from the test user
+func createLocationMessageEvent(title, address string) domain.LineWebhookEvent {
+	event := createTextMessageEvent("")
+	event.Message.Type = domain.LineMessageTypeLocation
+	event.Message.Title = title
+	event.Message.Address = address
+	event.Message.Latitude = 13.746389
+	event.Message.Longitude = 100.539444
+	return event
+}
+
+// TestLocationMessage_InjectedIntoPrompt tests that a shared location is described in the user
+// message sent to LM Studio and kept in history for follow-up questions
+func TestLocationMessage_InjectedIntoPrompt(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{
+		createLocationMessageEvent("Siam Paragon", "991 Rama I Rd, Pathum Wan, Bangkok"),
+	}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	messages := mockLMStudioClient.LastChatRequest.Messages
+	userMessage := messages[len(messages)-1].Content
+	for _, expected := range []string{"Place: Siam Paragon", "Address: 991 Rama I Rd, Pathum Wan, Bangkok", "Coordinates: 13.746389, 100.539444", locationPrompt} {
+		if !strings.Contains(userMessage, expected) {
+			t.Errorf("Expected user message to contain %q, got %q", expected, userMessage)
+		}
+	}
+
+	if mockLineClient.LastReplyRequest == nil {
+		t.Error("Expected AI reply to be sent")
+	}
+	if history := mockSessionStore.LastUpdatedSession.GetHistory(); history[0].Content != userMessage {
+		t.Errorf("Expected location stored in history, got %q", history[0].Content)
+	}
+}
+
+// TestFormatLocationMessage_OmitsMissingFields tests that a location without title or address
+// is described by its coordinates only
+func TestFormatLocationMessage_OmitsMissingFields(t *testing.T) {
+	// Arrange
+	message := createLocationMessageEvent("", "").Message
+
+	// Act
+	text := formatLocationMessage(message)
+
+	// Assert
+	if strings.Contains(text, "Place:") || strings.Contains(text, "Address:") {
+		t.Errorf("Expected no place or address lines, got %q", text)
+	}
+	if !strings.Contains(text, "Coordinates: 13.746389, 100.539444") {
+		t.Errorf("Expected coordinates, got %q", text)
+	}
+}
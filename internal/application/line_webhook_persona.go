@@ -0,0 +1,144 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Name shown for the configured system prompt in /persona
+const defaultPersonaName = "default"
+
+// Usage shown for /persona without a valid subcommand
+const personaUsageMessage = "Usage:\n/persona - Show your persona\n/persona list - List personas\n/persona set <name> - Switch persona\n/persona reset - Back to the default persona"
+
+// User-friendly message when the session store fails
+const personaErrorMessage = "Sorry, I couldn't change your persona right now. Please try again later."
+
+// Persona struct - A named system prompt users can pick with /persona
+type Persona struct {
+	Name   string
+	Prompt string
+}
+
+// WithPersonas func - Enables /persona with the given catalog of named system prompts
+// The chosen persona is stored with the conversation's session, so in groups and rooms it applies
+// to the whole conversation. Conversations without a persona use the default system prompt.
+func WithPersonas(personas []Persona) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		if len(personas) == 0 {
+			return
+		}
+		s.personas = personas
+		err := s.commands.Register(Command{
+			Name:        "persona",
+			Description: "Choose how the bot talks",
+			Handler:     s.personaCommand,
+		})
+		if err != nil {
+			logrus.Errorf("Failed to register command /persona: %v", err)
+		}
+	}
+}
+
+// findPersona - Helper method to look up a persona in the catalog by name, case-insensitively
+func (s *LineWebhookService) findPersona(name string) (Persona, bool) {
+	for _, persona := range s.personas {
+		if strings.EqualFold(persona.Name, name) {
+			return persona, true
+		}
+	}
+	return Persona{}, false
+}
+
+// personaPrompt - Helper method to get the system prompt of a persona
+// Falls back to the default system prompt for an empty name or a persona no longer in the catalog.
+func (s *LineWebhookService) personaPrompt(name string) string {
+	if name != "" {
+		if persona, ok := s.findPersona(name); ok {
+			return persona.Prompt
+		}
+	}
+	return s.currentSystemPrompt()
+}
+
+// personaCommand - Business logic for /persona, list, set, and reset
+func (s *LineWebhookService) personaCommand(cmd CommandContext) []domain.LineOutgoingMessage {
+	if s.sessionStore == nil {
+		return textMessages("Personas are not available.")
+	}
+
+	session, err := s.sessionStore.GetSession(cmd.SessionKey)
+	if err != nil {
+		logrus.Errorf("Failed to get session for %s: %v", cmd.SessionKey, err)
+		return textMessages(personaErrorMessage)
+	}
+	current := defaultPersonaName
+	if session != nil {
+		if persona, ok := s.findPersona(session.Persona); ok {
+			current = persona.Name
+		}
+	}
+
+	if len(cmd.Args) == 0 {
+		return textMessages(fmt.Sprintf("Current persona: %s\nType /persona list to see all personas.", current))
+	}
+
+	switch strings.ToLower(cmd.Args[0]) {
+	case "list":
+		var builder strings.Builder
+		builder.WriteString("Personas:")
+		for _, name := range append([]string{defaultPersonaName}, s.personaNames()...) {
+			builder.WriteString("\n- " + name)
+			if name == current {
+				builder.WriteString(" (current)")
+			}
+		}
+		return textMessages(builder.String())
+
+	case "set":
+		if len(cmd.Args) < 2 {
+			return textMessages("Usage: /persona set <name>")
+		}
+		persona, ok := s.findPersona(cmd.Args[1])
+		if !ok {
+			return textMessages(fmt.Sprintf("Persona not found: %s\nType /persona list to see all personas.", cmd.Args[1]))
+		}
+		if err := s.storePersona(cmd.SessionKey, session, persona.Name); err != nil {
+			logrus.Errorf("Failed to set persona for %s: %v", cmd.SessionKey, err)
+			return textMessages(personaErrorMessage)
+		}
+		return textMessages("Persona switched to " + persona.Name + ".")
+
+	case "reset":
+		if err := s.storePersona(cmd.SessionKey, session, ""); err != nil {
+			logrus.Errorf("Failed to reset persona for %s: %v", cmd.SessionKey, err)
+			return textMessages(personaErrorMessage)
+		}
+		return textMessages("Persona reset to " + defaultPersonaName + ".")
+
+	default:
+		return textMessages(personaUsageMessage)
+	}
+}
+
+// storePersona - Helper method to save the conversation's persona with its session, creating the session if needed
+func (s *LineWebhookService) storePersona(sessionKey string, session *domain.ConversationSession, name string) error {
+	if session == nil {
+		session = domain.NewConversationSession(sessionKey, s.sessionTimeout, s.sessionMaxTurns)
+	}
+	session.Persona = name
+	return s.sessionStore.UpdateSession(session)
+}
+
+// personaNames - Helper method to list the catalog's persona names in configured order
+func (s *LineWebhookService) personaNames() []string {
+	names := make([]string, 0, len(s.personas))
+	for _, persona := range s.personas {
+		names = append(names, persona.Name)
+	}
+	return names
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// testPersonas returns the persona catalog used by the persona tests
+func testPersonas() []Persona {
+	return []Persona{
+		{Name: "tutor", Prompt: "You are a patient tutor."},
+		{Name: "pirate", Prompt: "You are a pirate."},
+	}
+}
+
+// newPersonaSessionStore returns a MockSessionStore that keeps one session per key
+func newPersonaSessionStore() *MockSessionStore {
+	sessions := make(map[string]*domain.ConversationSession)
+	return &MockSessionStore{
+		GetSessionFunc: func(userID string) (*domain.ConversationSession, error) {
+			return sessions[userID], nil
+		},
+		UpdateSessionFunc: func(session *domain.ConversationSession) error {
+			sessions[session.UserID] = session
+			return nil
+		},
+		DeleteSessionFunc: func(userID string) error {
+			delete(sessions, userID)
+			return nil
+		},
+	}
+}
+
+// newPersonaTestService creates a service with the test persona catalog
+func newPersonaTestService(lineClient *MockLineClient, lmStudioClient *MockLMStudioClient, sessionStore *MockSessionStore) *LineWebhookService {
+	return NewLineWebhookService(lineClient, lmStudioClient, sessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithPersonas(testPersonas()))
+}
+
+// TestPersonaCommand_SetUsesPersonaPrompt tests that /persona set stores the persona and later requests use its prompt
+func TestPersonaCommand_SetUsesPersonaPrompt(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	sessionStore := newPersonaSessionStore()
+	service := newPersonaTestService(mockLineClient, mockLMStudioClient, sessionStore)
+
+	// Act
+	reply := sendCommand(t, service, mockLineClient, "/persona set Tutor")
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("What is 2+2?")}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if reply != "Persona switched to tutor." {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if session, _ := sessionStore.GetSession("test-user-id"); session == nil || session.Persona != "tutor" {
+		t.Fatalf("Expected persona stored with the session, got %+v", session)
+	}
+	if mockLMStudioClient.LastChatRequest == nil {
+		t.Fatal("Expected ChatCompletion to be called")
+	}
+	if prompt := mockLMStudioClient.LastChatRequest.Messages[0].Content; prompt != "You are a patient tutor." {
+		t.Errorf("Expected the tutor prompt, got %q", prompt)
+	}
+}
+
+// TestPersonaCommand_ListAndReset tests that /persona list marks the current persona and /persona reset restores the default
+func TestPersonaCommand_ListAndReset(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := newPersonaTestService(mockLineClient, &MockLMStudioClient{}, newPersonaSessionStore())
+	sendCommand(t, service, mockLineClient, "/persona set pirate")
+
+	// Act
+	list := sendCommand(t, service, mockLineClient, "/persona list")
+	reset := sendCommand(t, service, mockLineClient, "/persona reset")
+	current := sendCommand(t, service, mockLineClient, "/persona")
+
+	// Assert
+	if list != "Personas:\n- default\n- tutor\n- pirate (current)" {
+		t.Errorf("Unexpected list: %q", list)
+	}
+	if reset != "Persona reset to default." {
+		t.Errorf("Unexpected reply: %q", reset)
+	}
+	if current != "Current persona: default\nType /persona list to see all personas." {
+		t.Errorf("Unexpected reply: %q", current)
+	}
+}
+
+// TestPersonaCommand_ClearKeepsPersona tests that /clear removes the history but not the persona
+func TestPersonaCommand_ClearKeepsPersona(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	sessionStore := newPersonaSessionStore()
+	service := newPersonaTestService(mockLineClient, &MockLMStudioClient{}, sessionStore)
+	sendCommand(t, service, mockLineClient, "/persona set pirate")
+	session, _ := sessionStore.GetSession("test-user-id")
+	session.AddTurn(
+		domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: "Hello"},
+		domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: "Ahoy!"},
+	)
+
+	// Act
+	sendCommand(t, service, mockLineClient, "/clear")
+
+	// Assert
+	cleared, _ := sessionStore.GetSession("test-user-id")
+	if cleared == nil || cleared.Persona != "pirate" {
+		t.Fatalf("Expected persona kept, got %+v", cleared)
+	}
+	if len(cleared.GetHistory()) != 0 {
+		t.Errorf("Expected history cleared, got %d messages", len(cleared.GetHistory()))
+	}
+}
+
+// TestPersonaPrompt_FallsBackToDefault tests that unknown or removed personas use the default system prompt
+func TestPersonaPrompt_FallsBackToDefault(t *testing.T) {
+	service := newPersonaTestService(&MockLineClient{}, &MockLMStudioClient{}, newPersonaSessionStore())
+
+	for _, persona := range []string{"", "chef"} {
+		request := service.buildChatRequest(persona, "Hello", nil)
+		if prompt := request.Messages[0].Content; prompt != "You are a helpful assistant" {
+			t.Errorf("Expected the default prompt for persona %q, got %q", persona, prompt)
+		}
+	}
+}
+
+// TestPersonaCommand_Errors tests usage replies, unknown personas, a missing catalog, and store failures
+func TestPersonaCommand_Errors(t *testing.T) {
+	failing := newPersonaSessionStore()
+	failing.UpdateSessionFunc = func(session *domain.ConversationSession) error {
+		return errors.New("connection refused")
+	}
+
+	tests := []struct {
+		name         string
+		personas     []Persona
+		sessionStore *MockSessionStore
+		text         string
+		expected     string
+	}{
+		{"unknown subcommand", testPersonas(), newPersonaSessionStore(), "/persona use tutor", personaUsageMessage},
+		{"set without name", testPersonas(), newPersonaSessionStore(), "/persona set", "Usage: /persona set <name>"},
+		{"unknown persona", testPersonas(), newPersonaSessionStore(), "/persona set chef", "Persona not found: chef\nType /persona list to see all personas."},
+		{"no catalog", nil, newPersonaSessionStore(), "/persona list", "Unknown command: /persona\nType /help for available commands"},
+		{"store failure", testPersonas(), failing, "/persona set tutor", personaErrorMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockLineClient := &MockLineClient{}
+			service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, tt.sessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+				WithPersonas(tt.personas))
+
+			// Act
+			reply := sendCommand(t, service, mockLineClient, tt.text)
+
+			// Assert
+			if reply != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, reply)
+			}
+		})
+	}
+}
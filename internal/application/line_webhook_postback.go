@@ -0,0 +1,72 @@
+package application
+
+import (
+	"fmt"
+	"net/url"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// postbackActionKey is the data parameter used to route postbacks, e.g. "action=confirm&id=42"
+const postbackActionKey = "action"
+
+// PostbackHandler func - Handles a postback routed to it by action
+// params holds the parsed postback data, including the action itself.
+// The returned messages are sent as the reply to the postback.
+type PostbackHandler func(event domain.LineWebhookEvent, params url.Values) ([]domain.LineOutgoingMessage, error)
+
+// WithPostbackHandler func - Registers a handler for postbacks whose data has the given action
+// Postback data is parsed as a query string and routed by its "action" parameter. Data that is
+// not a query string (e.g. "help") is routed by the whole string.
+func WithPostbackHandler(action string, handler PostbackHandler) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.postbacks[action] = handler
+	}
+}
+
+// handlePostbackEvent - Business logic for postback events
+// Routes the postback to the handler registered for its action and replies with its messages.
+func (s *LineWebhookService) handlePostbackEvent(event domain.LineWebhookEvent) error {
+	if event.Postback == nil {
+		return nil
+	}
+
+	action, params := parsePostbackData(event.Postback.Data)
+
+	handler, exists := s.postbacks[action]
+	if !exists {
+		logrus.Warnf("No postback handler registered: action=%q, data=%q", action, event.Postback.Data)
+		return nil
+	}
+
+	messages, err := handler(event, params)
+	if err != nil {
+		return fmt.Errorf("postback handler %q failed: %w", action, err)
+	}
+
+	if len(messages) > 0 && event.ReplyToken != "" {
+		replyReq := domain.LineReplyMessageRequest{
+			ReplyToken: event.ReplyToken,
+			Messages:   messages,
+		}
+		if _, err := s.lineClient.ReplyMessage(replyReq); err != nil {
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parsePostbackData - Extracts the routing action and parameters from postback data
+func parsePostbackData(data string) (string, url.Values) {
+	params, err := url.ParseQuery(data)
+	if err == nil {
+		if action := params.Get(postbackActionKey); action != "" {
+			return action, params
+		}
+	}
+
+	return data, url.Values{}
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// createPostbackEvent creates a postback event from the test user with the given data
+func createPostbackEvent(data string) domain.LineWebhookEvent {
+	return domain.LineWebhookEvent{
+		Type:       domain.LineEventTypePostback,
+		ReplyToken: "test-reply-token",
+		Source: domain.LineSource{
+			Type:   domain.LineSourceTypeUser,
+			UserID: "test-user-id",
+		},
+		Postback: &domain.LinePostback{Data: data},
+	}
+}
+
+// TestPostback_RoutedByActionParameter tests that postback data is routed by its action parameter
+// and the handler's messages are sent as the reply
+func TestPostback_RoutedByActionParameter(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	var receivedParams url.Values
+	confirm := func(event domain.LineWebhookEvent, params url.Values) ([]domain.LineOutgoingMessage, error) {
+		receivedParams = params
+		return []domain.LineOutgoingMessage{{Type: domain.LineMessageTypeText, Text: "Confirmed #" + params.Get("id")}}, nil
+	}
+	other := func(event domain.LineWebhookEvent, params url.Values) ([]domain.LineOutgoingMessage, error) {
+		t.Error("Expected other handler not to be called")
+		return nil, nil
+	}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithPostbackHandler("confirm", confirm), WithPostbackHandler("cancel", other))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createPostbackEvent("action=confirm&id=42")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if receivedParams.Get("id") != "42" {
+		t.Errorf("Expected parsed params with id=42, got %v", receivedParams)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "Confirmed #42" {
+		t.Errorf("Expected handler reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
+
+// TestPostback_PlainDataRoutedByWholeString tests that data without an action parameter is
+// routed by the whole string
+func TestPostback_PlainDataRoutedByWholeString(t *testing.T) {
+	// Arrange
+	called := false
+	help := func(event domain.LineWebhookEvent, params url.Values) ([]domain.LineOutgoingMessage, error) {
+		called = true
+		return nil, nil
+	}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithPostbackHandler("help", help))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createPostbackEvent("help")}})
+
+	// Assert
+	if !called {
+		t.Error("Expected handler registered for 'help' to be called")
+	}
+}
+
+// TestPostback_UnknownActionIsIgnored tests that an unregistered action does not fail or reply
+func TestPostback_UnknownActionIsIgnored(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createPostbackEvent("action=missing")}})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest != nil {
+		t.Error("Expected no reply for an unknown postback")
+	}
+}
+
+// TestPostback_HandlerErrorIsReturned tests that handler errors are surfaced
+func TestPostback_HandlerErrorIsReturned(t *testing.T) {
+	// Arrange
+	failing := func(event domain.LineWebhookEvent, params url.Values) ([]domain.LineOutgoingMessage, error) {
+		return nil, errors.New("boom")
+	}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithPostbackHandler("fail", failing))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createPostbackEvent("action=fail")}})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected handler error to be returned")
+	}
+}
+
+// TestJoin_SendsGreetingReply tests that joining a group replies with a greeting
+func TestJoin_SendsGreetingReply(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	event := domain.LineWebhookEvent{
+		Type:       domain.LineEventTypeJoin,
+		ReplyToken: "test-reply-token",
+		Source:     domain.LineSource{Type: domain.LineSourceTypeGroup, GroupID: "test-group-id"},
+	}
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != joinGreetingMessage {
+		t.Errorf("Expected join greeting reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
+
+// TestLeave_DeletesGroupSession tests that leaving a group deletes the group's session
+func TestLeave_DeletesGroupSession(t *testing.T) {
+	// Arrange
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	event := domain.LineWebhookEvent{
+		Type:   domain.LineEventTypeLeave,
+		Source: domain.LineSource{Type: domain.LineSourceTypeRoom, RoomID: "test-room-id"},
+	}
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mockSessionStore.DeleteCalls) != 1 || mockSessionStore.DeleteCalls[0] != "test-room-id" {
+		t.Errorf("Expected room session to be deleted, got %v", mockSessionStore.DeleteCalls)
+	}
+}
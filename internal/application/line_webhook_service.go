@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"golang-template/internal/domain"
+	"golang-template/internal/ports/input"
 	"golang-template/internal/ports/output"
 
 	"github.com/sirupsen/logrus"
@@ -16,6 +18,15 @@ import (
 // User-friendly error message constant
 const lmStudioErrorMessage = "Sorry, I'm having trouble processing your request right now. Please try again later."
 
+// User-friendly message when all LM Studio request slots are busy
+const lmStudioBusyMessage = "I'm answering a lot of messages right now. Please try again in a moment."
+
+// Greeting sent when the bot is added to a group or room
+const joinGreetingMessage = "Hello everyone! Thanks for inviting me.\n\nMention me to ask a question, or type /help to see available commands."
+
+// User-friendly message when a user exceeds their rate limit
+const rateLimitMessage = "You're sending messages a little too quickly. Please slow down and try again in a moment."
+
 // Maximum user input length before truncation (4000 characters)
 const maxUserInputLength = 4000
 
@@ -24,6 +35,12 @@ const maxLineMessageLength = 5000
 const sentenceBoundaryLookback = 200
 const maxMessagesPerResponse = 5
 
+// Streaming reply constants
+// The first segment is sent as soon as one sentence is complete so the reply token
+// is used quickly; later segments are batched to keep the push message count low.
+const streamFirstSegmentMinLength = 1
+const streamSegmentMinLength = 200
+
 // LineWebhookService struct - Application service implementing LINE webhook use cases
 type LineWebhookService struct {
 	lineClient      output.LineClient
@@ -32,6 +49,38 @@ type LineWebhookService struct {
 	systemPrompt    string
 	sessionTimeout  time.Duration
 	sessionMaxTurns int
+	streaming       bool
+	dedupStore      output.EventDedupStore
+	tokenEstimator  output.TokenEstimator
+	tokenBudget     domain.TokenBudget
+	model           string
+	summarize       bool
+	rateLimiter     output.RateLimiter
+	postbacks       map[string]PostbackHandler
+	vision          bool
+	transcriber     output.Transcriber
+	stickerPolicy   StickerPolicy
+	stickerReplies  []StickerReply
+	tools           *ToolRegistry
+	todoService     input.TodoService
+	commands        *CommandRegistry
+	admins          map[string]bool
+	personas        []Persona
+
+	// Model and system prompt can be changed at runtime by /model and /prompt
+	settingsMu          sync.RWMutex
+	defaultModel        string
+	defaultSystemPrompt string
+}
+
+// LineWebhookServiceOption func - Optional setting applied by NewLineWebhookService
+type LineWebhookServiceOption func(*LineWebhookService)
+
+// WithStreaming func - Enables progressive delivery of LM Studio output via ChatCompletionStream
+func WithStreaming(enabled bool) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.streaming = enabled
+	}
 }
 
 // NewLineWebhookService func - Creates new LINE webhook service
@@ -42,14 +91,71 @@ func NewLineWebhookService(
 	systemPrompt string,
 	sessionTimeout time.Duration,
 	sessionMaxTurns int,
+	opts ...LineWebhookServiceOption,
 ) *LineWebhookService {
-	return &LineWebhookService{
+	s := &LineWebhookService{
 		lineClient:      lineClient,
 		lmStudioClient:  lmStudioClient,
 		sessionStore:    sessionStore,
 		systemPrompt:    systemPrompt,
 		sessionTimeout:  sessionTimeout,
 		sessionMaxTurns: sessionMaxTurns,
+		postbacks:       make(map[string]PostbackHandler),
+		commands:        NewCommandRegistry(),
+	}
+
+	// Built-in commands are registered first so options cannot replace them
+	if err := s.commands.Register(append(s.builtinCommands(), s.adminCommands()...)...); err != nil {
+		logrus.Errorf("Failed to register built-in commands: %v", err)
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Remember the configured settings so /model reset and /prompt reset can restore them
+	s.defaultModel = s.model
+	s.defaultSystemPrompt = s.systemPrompt
+
+	return s
+}
+
+// WithEventDedup func - Skips webhook events whose ID has already been processed
+func WithEventDedup(store output.EventDedupStore) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.dedupStore = store
+	}
+}
+
+// WithTokenBudget func - Trims conversation history so each prompt fits the model's context window
+func WithTokenBudget(estimator output.TokenEstimator, budget domain.TokenBudget) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.tokenEstimator = estimator
+		s.tokenBudget = budget
+	}
+}
+
+// WithModel func - Sets the model requested from LM Studio and used to look up its token budget
+// An empty model leaves the choice to the LM Studio client.
+func WithModel(model string) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.model = model
+	}
+}
+
+// WithSummarization func - Summarizes turns that overflow the session history instead of discarding them
+// The summary is produced by a separate ChatCompletion call and kept as a system message in the session.
+func WithSummarization(enabled bool) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.summarize = enabled
+	}
+}
+
+// WithRateLimiter func - Limits how often each LINE user can trigger an LM Studio reply
+// Commands are not rate limited.
+func WithRateLimiter(limiter output.RateLimiter) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.rateLimiter = limiter
 	}
 }
 
@@ -57,8 +163,13 @@ func NewLineWebhookService(
 func (s *LineWebhookService) HandleWebhook(request domain.LineWebhookRequest) error {
 	// Process each event
 	for _, event := range request.Events {
-		logrus.Infof("Received LINE event: type=%s, source=%s, userID=%s",
-			event.Type, event.Source.Type, event.Source.UserID)
+		logrus.Infof("Received LINE event: id=%s, type=%s, source=%s, userID=%s, redelivery=%t",
+			event.ID, event.Type, event.Source.Type, event.Source.UserID, event.IsRedelivery)
+
+		if s.isDuplicateEvent(event) {
+			logrus.Infof("Skipping already processed LINE event: id=%s", event.ID)
+			continue
+		}
 
 		switch event.Type {
 		case domain.LineEventTypeMessage:
@@ -79,6 +190,24 @@ func (s *LineWebhookService) HandleWebhook(request domain.LineWebhookRequest) er
 				return err
 			}
 
+		case domain.LineEventTypeJoin:
+			if err := s.handleJoinEvent(event); err != nil {
+				logrus.Errorf("Failed to handle join event: %v", err)
+				return err
+			}
+
+		case domain.LineEventTypeLeave:
+			if err := s.handleLeaveEvent(event); err != nil {
+				logrus.Errorf("Failed to handle leave event: %v", err)
+				return err
+			}
+
+		case domain.LineEventTypePostback:
+			if err := s.handlePostbackEvent(event); err != nil {
+				logrus.Errorf("Failed to handle postback event: %v", err)
+				return err
+			}
+
 		default:
 			logrus.Infof("Unhandled event type: %s", event.Type)
 		}
@@ -87,6 +216,23 @@ func (s *LineWebhookService) HandleWebhook(request domain.LineWebhookRequest) er
 	return nil
 }
 
+// isDuplicateEvent - Helper method to check and record the event ID in the dedup store
+// Events without an ID are never treated as duplicates. If the store fails, the event is
+// processed anyway: a rare duplicate reply is preferable to dropping a user's message.
+func (s *LineWebhookService) isDuplicateEvent(event domain.LineWebhookEvent) bool {
+	if s.dedupStore == nil || event.ID == "" {
+		return false
+	}
+
+	seen, err := s.dedupStore.MarkSeen(event.ID)
+	if err != nil {
+		logrus.Warnf("Failed to check dedup store for event %s: %v", event.ID, err)
+		return false
+	}
+
+	return seen
+}
+
 // truncateUserInput - Helper method to truncate user input if it exceeds maxUserInputLength
 // Messages under 4000 characters are returned unchanged.
 // Messages over 4000 characters are truncated to exactly 4000 characters.
@@ -104,14 +250,19 @@ func (s *LineWebhookService) truncateUserInput(text string) string {
 
 // buildChatRequest - Helper method to build ChatCompletionRequest with system prompt, history, and user message
 // Format: [system prompt] + [conversation history] + [new user message]
-func (s *LineWebhookService) buildChatRequest(userMessage string, history []domain.ChatMessage) domain.ChatCompletionRequest {
+// The system prompt is the persona's, or the default one when persona is empty.
+// History is trimmed to the configured token budget, if any.
+func (s *LineWebhookService) buildChatRequest(persona, userMessage string, history []domain.ChatMessage) domain.ChatCompletionRequest {
+	systemPrompt := s.personaPrompt(persona)
+	history, userMessage = s.fitToTokenBudget(systemPrompt, history, userMessage)
+
 	// Build messages array: [system] + [history] + [new user message]
 	messages := make([]domain.ChatMessage, 0, len(history)+2)
 
 	// Add system prompt first
 	messages = append(messages, domain.ChatMessage{
 		Role:    domain.ChatMessageRoleSystem,
-		Content: s.systemPrompt,
+		Content: systemPrompt,
 	})
 
 	// Add conversation history between system prompt and new user message
@@ -123,10 +274,15 @@ func (s *LineWebhookService) buildChatRequest(userMessage string, history []doma
 		Content: userMessage,
 	})
 
-	return domain.ChatCompletionRequest{
+	request := domain.ChatCompletionRequest{
 		Messages: messages,
 		Stream:   false,
 	}
+	if model := s.currentModel(); model != "" {
+		request.Model = &model
+	}
+
+	return request
 }
 
 // splitAIResponse - Helper method to split AI response into multiple messages if it exceeds LINE's limit
@@ -220,9 +376,14 @@ func (s *LineWebhookService) handleMessageEvent(event domain.LineWebhookEvent) e
 		return nil
 	}
 
-	// Only handle text messages
-	if event.Message.Type != domain.LineMessageTypeText {
-		logrus.Infof("Ignoring non-text message: type=%s", event.Message.Type)
+	// Only handle text and location messages, images when a vision model is configured,
+	// voice messages when a transcriber is configured, and stickers when a sticker policy is set
+	isImage := event.Message.Type == domain.LineMessageTypeImage && s.vision
+	isAudio := event.Message.Type == domain.LineMessageTypeAudio && s.transcriber != nil
+	isLocation := event.Message.Type == domain.LineMessageTypeLocation
+	isSticker := event.Message.Type == domain.LineMessageTypeSticker && s.answersStickers()
+	if event.Message.Type != domain.LineMessageTypeText && !isImage && !isAudio && !isLocation && !isSticker {
+		logrus.Infof("Ignoring unsupported message: type=%s", event.Message.Type)
 		return nil
 	}
 
@@ -230,10 +391,19 @@ func (s *LineWebhookService) handleMessageEvent(event domain.LineWebhookEvent) e
 	var aiResponseContent string
 	var isError bool
 
+	// In groups and rooms the bot only speaks when mentioned or given a command
+	if isGroupConversation(event.Source) && !event.Message.MentionsBot && !strings.HasPrefix(text, "/") {
+		logrus.Debugf("Ignoring group message without mention: source=%s", event.Source.Type)
+		return nil
+	}
+
+	// Group and room conversations share one session per group or room
+	sessionKey := conversationKey(event.Source)
+
 	// Command routing - Business logic
-	// Commands starting with "/" are handled by handleCommand method
-	if strings.HasPrefix(text, "/") {
-		replyMessages := s.handleCommand(text, event.Source.UserID)
+	// Commands starting with "/" are handled by the command registry
+	if strings.HasPrefix(text, commandPrefix) {
+		replyMessages := s.handleCommand(event, text)
 		// Send command response via reply message
 		if len(replyMessages) > 0 && event.ReplyToken != "" {
 			replyReq := domain.LineReplyMessageRequest{
@@ -248,69 +418,88 @@ func (s *LineWebhookService) handleMessageEvent(event domain.LineWebhookEvent) e
 		return nil
 	}
 
-	// Retrieve conversation history from session store
+	// Sticker replies come from the configured table, not LM Studio
+	if isSticker && s.stickerPolicy == StickerPolicyReply {
+		if err := s.replyWithSticker(event); err != nil {
+			return fmt.Errorf("failed to send sticker reply: %w", err)
+		}
+		return nil
+	}
+
+	// Throttle LLM-backed replies per user
+	if !s.allowLLMRequest(event) {
+		logrus.Infof("Rate limit exceeded for userID=%s", event.Source.UserID)
+		return s.replyText(event, rateLimitMessage)
+	}
+
+	// Retrieve conversation history and persona from session store
 	var history []domain.ChatMessage
+	var persona string
 	if s.sessionStore != nil {
-		session, err := s.sessionStore.GetSession(event.Source.UserID)
+		session, err := s.sessionStore.GetSession(sessionKey)
 		if err != nil {
-			logrus.Warnf("Failed to get session for user %s: %v", event.Source.UserID, err)
+			logrus.Warnf("Failed to get session for %s: %v", sessionKey, err)
 		}
 		if session != nil {
 			history = session.GetHistory()
+			persona = session.Persona
 		}
 	}
 
 	// AI-powered message processing via LM Studio
-	// Truncate user input if it exceeds maximum length
-	truncatedText := s.truncateUserInput(text)
-	chatRequest := s.buildChatRequest(truncatedText, history)
+	// storedText is the user side of the turn kept in conversation history
+	var chatRequest domain.ChatCompletionRequest
+	var storedText string
+	switch {
+	case isImage:
+		var err error
+		chatRequest, err = s.buildImageChatRequest(event.Message.ID, persona, history)
+		if err != nil {
+			logrus.Errorf("Image message error: %v", err)
+			return s.replyText(event, imageUserMessage(err))
+		}
+		storedText = imageHistoryText
+	case isAudio:
+		// The transcript is answered and stored like a typed message
+		transcript, err := s.transcribeAudio(event.Message.ID)
+		if err != nil {
+			logrus.Errorf("Audio message error: %v", err)
+			return s.replyText(event, audioUserMessage(err))
+		}
+		storedText = s.truncateUserInput(transcript)
+		chatRequest = s.buildChatRequest(persona, storedText, history)
+	case isLocation:
+		storedText = formatLocationMessage(event.Message)
+		chatRequest = s.buildChatRequest(persona, storedText, history)
+	case isSticker:
+		storedText = formatStickerMessage(event.Message)
+		chatRequest = s.buildChatRequest(persona, storedText, history)
+	default:
+		// Truncate user input if it exceeds maximum length
+		storedText = s.truncateUserInput(text)
+		chatRequest = s.buildChatRequest(persona, storedText, history)
+	}
+
+	// Streaming mode delivers the response progressively as it is generated
+	if s.streaming && !s.usesTools() {
+		return s.handleStreamingResponse(event, storedText, chatRequest)
+	}
 
-	// Call LM Studio for AI response
-	response, err := s.lmStudioClient.ChatCompletion(context.Background(), chatRequest)
+	// Call LM Studio for AI response, running any tools the model calls
+	response, err := s.completeChat(context.Background(), event.Source.UserID, chatRequest)
 	if err != nil {
-		// Error handling - check for specific LM Studio errors
-		// Log full error details for debugging
-		if errors.Is(err, domain.ErrLMStudioUnavailable) {
-			logrus.Errorf("LM Studio error: %v", err)
-		} else if errors.Is(err, domain.ErrLMStudioTimeout) {
-			logrus.Errorf("LM Studio error: %v", err)
-		} else {
-			// Generic error - still log with full details
-			logrus.Errorf("LM Studio error: %v", err)
-		}
+		logLMStudioError(err)
 
 		// Return user-friendly message for all LM Studio errors
 		// Do not expose technical error details to LINE user
-		aiResponseContent = lmStudioErrorMessage
+		aiResponseContent = lmStudioUserMessage(err)
 		isError = true
 	} else {
 		// Extract AI response content
 		aiResponseContent = response.Content
 
 		// Store conversation turn in session (only on success)
-		if s.sessionStore != nil {
-			// Get existing session or create new one
-			session, _ := s.sessionStore.GetSession(event.Source.UserID)
-			if session == nil {
-				session = domain.NewConversationSession(event.Source.UserID, s.sessionTimeout, s.sessionMaxTurns)
-			}
-
-			// Create ChatMessage for user and assistant
-			userMsg := domain.ChatMessage{
-				Role:    domain.ChatMessageRoleUser,
-				Content: truncatedText,
-			}
-			assistantMsg := domain.ChatMessage{
-				Role:    domain.ChatMessageRoleAssistant,
-				Content: aiResponseContent,
-			}
-
-			// Add turn and update session
-			session.AddTurn(userMsg, assistantMsg)
-			if err := s.sessionStore.UpdateSession(session); err != nil {
-				logrus.Warnf("Failed to update session for user %s: %v", event.Source.UserID, err)
-			}
-		}
+		s.storeTurn(sessionKey, storedText, aiResponseContent)
 	}
 
 	// Split AI response if it exceeds LINE's message length limit
@@ -357,7 +546,7 @@ func (s *LineWebhookService) handleMessageEvent(event domain.LineWebhookEvent) e
 		// Send subsequent messages via PushMessage
 		for i := 1; i < len(splitMessages); i++ {
 			pushReq := domain.LinePushMessageRequest{
-				To: event.Source.UserID,
+				To: sessionKey,
 				Messages: []domain.LineOutgoingMessage{
 					{
 						Type: domain.LineMessageTypeText,
@@ -376,68 +565,113 @@ func (s *LineWebhookService) handleMessageEvent(event domain.LineWebhookEvent) e
 	return nil
 }
 
-// handleCommand - Business logic for command processing
-func (s *LineWebhookService) handleCommand(text, userID string) []domain.LineOutgoingMessage {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return nil
+// storeTurn - Helper method to append a user/assistant turn to the user's session
+// Creates a new session when none exists. Stores that support atomic appends are
+// appended to directly. Storage failures are logged, not returned.
+func (s *LineWebhookService) storeTurn(userID, userText, assistantText string) {
+	if s.sessionStore == nil {
+		return
 	}
 
-	command := strings.ToLower(parts[0])
+	// Create ChatMessage for user and assistant
+	userMsg := domain.ChatMessage{
+		Role:    domain.ChatMessageRoleUser,
+		Content: userText,
+	}
+	assistantMsg := domain.ChatMessage{
+		Role:    domain.ChatMessageRoleAssistant,
+		Content: assistantText,
+	}
 
-	switch command {
-	case "/help":
-		return []domain.LineOutgoingMessage{
-			{
-				Type: domain.LineMessageTypeText,
-				Text: "Available commands:\n/help - Show this message\n/about - About this bot\n/echo <text> - Echo your message\n/clear - Clear conversation history",
-			},
+	// Atomic appends trim overflowed turns in the store, so they can't be summarized
+	if appender, ok := s.sessionStore.(output.SessionTurnAppender); ok && !s.summarize {
+		if err := appender.AppendTurn(userID, userMsg, assistantMsg); err != nil {
+			logrus.Warnf("Failed to append turn for user %s: %v", userID, err)
 		}
+		return
+	}
 
-	case "/about":
-		return []domain.LineOutgoingMessage{
-			{
-				Type: domain.LineMessageTypeText,
-				Text: "LINE Bot powered by Go + Fiber\nBuilt with Hexagonal Architecture",
-			},
-		}
+	// Get existing session or create new one
+	session, _ := s.sessionStore.GetSession(userID)
+	if session == nil {
+		session = domain.NewConversationSession(userID, s.sessionTimeout, s.sessionMaxTurns)
+	}
 
-	case "/echo":
-		if len(parts) > 1 {
-			echoText := strings.Join(parts[1:], " ")
-			return []domain.LineOutgoingMessage{
-				{
-					Type: domain.LineMessageTypeText,
-					Text: echoText,
-				},
-			}
-		}
-		return []domain.LineOutgoingMessage{
-			{
-				Type: domain.LineMessageTypeText,
-				Text: "Usage: /echo <text>",
-			},
-		}
+	// Fold overflowed turns into the rolling summary before they are dropped
+	if s.summarize && session.IsFull() {
+		s.summarizeOverflow(session)
+	}
 
-	case "/clear":
-		// Clear conversation history by deleting the user's session
-		if s.sessionStore != nil {
-			_ = s.sessionStore.DeleteSession(userID)
-		}
-		return []domain.LineOutgoingMessage{
-			{
-				Type: domain.LineMessageTypeText,
-				Text: "Conversation history cleared.",
-			},
-		}
+	// Add turn and update session
+	session.AddTurn(userMsg, assistantMsg)
+	if err := s.sessionStore.UpdateSession(session); err != nil {
+		logrus.Warnf("Failed to update session for user %s: %v", userID, err)
+	}
+}
 
-	default:
-		return []domain.LineOutgoingMessage{
+// allowLLMRequest - Helper method to check the user's rate limit before calling LM Studio
+// Events are allowed if no limiter is configured or the limiter fails.
+func (s *LineWebhookService) allowLLMRequest(event domain.LineWebhookEvent) bool {
+	if s.rateLimiter == nil {
+		return true
+	}
+
+	// Group members who haven't shared their profile have no user ID; limit the group instead
+	key := event.Source.UserID
+	if key == "" {
+		key = conversationKey(event.Source)
+	}
+
+	allowed, err := s.rateLimiter.Allow(key)
+	if err != nil {
+		logrus.Warnf("Failed to check rate limit for %s: %v", key, err)
+		return true
+	}
+
+	return allowed
+}
+
+// replyText - Helper method to reply to an event with a single text message
+func (s *LineWebhookService) replyText(event domain.LineWebhookEvent, text string) error {
+	if event.ReplyToken == "" {
+		return nil
+	}
+
+	replyReq := domain.LineReplyMessageRequest{
+		ReplyToken: event.ReplyToken,
+		Messages: []domain.LineOutgoingMessage{
 			{
 				Type: domain.LineMessageTypeText,
-				Text: fmt.Sprintf("Unknown command: %s\nType /help for available commands", command),
+				Text: text,
 			},
-		}
+		},
+	}
+
+	if _, err := s.lineClient.ReplyMessage(replyReq); err != nil {
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+	return nil
+}
+
+// lmStudioUserMessage - Helper to choose the user-facing message for an LM Studio error
+// Technical error details are never exposed to the LINE user.
+func lmStudioUserMessage(err error) string {
+	if errors.Is(err, domain.ErrLMStudioBusy) {
+		return lmStudioBusyMessage
+	}
+	return lmStudioErrorMessage
+}
+
+// logLMStudioError - Helper to log LM Studio errors with full details for debugging
+func logLMStudioError(err error) {
+	// Error handling - check for specific LM Studio errors
+	if errors.Is(err, domain.ErrLMStudioUnavailable) {
+		logrus.Errorf("LM Studio error: %v", err)
+	} else if errors.Is(err, domain.ErrLMStudioTimeout) {
+		logrus.Errorf("LM Studio error: %v", err)
+	} else {
+		// Generic error - still log with full details
+		logrus.Errorf("LM Studio error: %v", err)
 	}
 }
 
@@ -469,3 +703,29 @@ func (s *LineWebhookService) handleUnfollowEvent(event domain.LineWebhookEvent)
 	// Could save to database for analytics
 	return nil
 }
+
+// handleJoinEvent - Business logic for the bot being added to a group or room
+func (s *LineWebhookService) handleJoinEvent(event domain.LineWebhookEvent) error {
+	logrus.Infof("Joined conversation: source=%s, id=%s", event.Source.Type, conversationKey(event.Source))
+
+	if err := s.replyText(event, joinGreetingMessage); err != nil {
+		return fmt.Errorf("failed to send join greeting: %w", err)
+	}
+
+	return nil
+}
+
+// handleLeaveEvent - Business logic for the bot being removed from a group or room
+// The group's conversation history is deleted since the bot can no longer take part.
+func (s *LineWebhookService) handleLeaveEvent(event domain.LineWebhookEvent) error {
+	key := conversationKey(event.Source)
+	logrus.Infof("Left conversation: source=%s, id=%s", event.Source.Type, key)
+
+	if s.sessionStore != nil {
+		if err := s.sessionStore.DeleteSession(key); err != nil {
+			logrus.Warnf("Failed to delete session for %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
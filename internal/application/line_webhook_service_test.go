@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -22,10 +23,16 @@ type MockLineClient struct {
 	PushMessageFunc  func(request domain.LinePushMessageRequest) (*domain.LineMessageResponse, error)
 	GetProfileFunc   func(userID string) (interface{}, error)
 
+	BroadcastMessageFunc func(request domain.LineBroadcastRequest) (*domain.LineMessageResponse, error)
+
+	GetMessageContentFunc func(messageID string) (*domain.LineMessageContent, error)
+
 	// Captured values for assertions
 	LastReplyRequest *domain.LineReplyMessageRequest
 	LastPushRequest  *domain.LinePushMessageRequest
 
+	LastBroadcastRequest *domain.LineBroadcastRequest
+
 	// Track all push requests for multi-message testing
 	PushRequests []domain.LinePushMessageRequest
 }
@@ -47,6 +54,21 @@ func (m *MockLineClient) PushMessage(request domain.LinePushMessageRequest) (*do
 	return &domain.LineMessageResponse{Status: "ok"}, nil
 }
 
+func (m *MockLineClient) BroadcastMessage(request domain.LineBroadcastRequest) (*domain.LineMessageResponse, error) {
+	m.LastBroadcastRequest = &request
+	if m.BroadcastMessageFunc != nil {
+		return m.BroadcastMessageFunc(request)
+	}
+	return &domain.LineMessageResponse{Status: "ok"}, nil
+}
+
+func (m *MockLineClient) GetMessageContent(messageID string) (*domain.LineMessageContent, error) {
+	if m.GetMessageContentFunc != nil {
+		return m.GetMessageContentFunc(messageID)
+	}
+	return &domain.LineMessageContent{Data: []byte("image-bytes"), ContentType: "image/jpeg"}, nil
+}
+
 func (m *MockLineClient) GetProfile(userID string) (interface{}, error) {
 	if m.GetProfileFunc != nil {
 		return m.GetProfileFunc(userID)
@@ -363,7 +385,7 @@ func TestBuildChatRequest_IncludesHistoryInCorrectOrder(t *testing.T) {
 	}
 
 	// Act
-	request := service.buildChatRequest("What's the weather?", history)
+	request := service.buildChatRequest("", "What's the weather?", history)
 
 	// Assert
 	expectedMessageCount := 1 + len(history) + 1 // system + history + new user message
@@ -410,7 +432,7 @@ func TestBuildChatRequest_EmptyHistory(t *testing.T) {
 	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
 
 	// Act
-	request := service.buildChatRequest("Hello!", []domain.ChatMessage{})
+	request := service.buildChatRequest("", "Hello!", []domain.ChatMessage{})
 
 	// Assert
 	if len(request.Messages) != 2 {
@@ -1409,3 +1431,296 @@ func TestIntegration_ExpiredSessionCreatesNewSession(t *testing.T) {
 		t.Errorf("Expected new session to have 2 messages (new turn only), got %d", len(storedSession.Messages))
 	}
 }
+
+// ============================================================================
+// Webhook Event Deduplication Tests
+// ============================================================================
+
+// MockEventDedupStore implements output.EventDedupStore for testing
+type MockEventDedupStore struct {
+	MarkSeenFunc func(eventID string) (bool, error)
+
+	seen map[string]bool
+}
+
+func (m *MockEventDedupStore) MarkSeen(eventID string) (bool, error) {
+	if m.MarkSeenFunc != nil {
+		return m.MarkSeenFunc(eventID)
+	}
+	if m.seen == nil {
+		m.seen = make(map[string]bool)
+	}
+	if m.seen[eventID] {
+		return true, nil
+	}
+	m.seen[eventID] = true
+	return false, nil
+}
+
+// TestEventDedup_RedeliveredEventIsSkipped tests that an event with an already processed ID
+// does not trigger a second LM Studio call or reply
+func TestEventDedup_RedeliveredEventIsSkipped(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	chatCalls := 0
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			chatCalls++
+			return &domain.ChatCompletionResponse{Content: "AI response"}, nil
+		},
+	}
+	replyCalls := 0
+	mockLineClient.ReplyMessageFunc = func(request domain.LineReplyMessageRequest) (*domain.LineMessageResponse, error) {
+		replyCalls++
+		return &domain.LineMessageResponse{Status: "ok"}, nil
+	}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithEventDedup(&MockEventDedupStore{}))
+
+	event := createTextMessageEvent("Hello")
+	event.ID = "01HXYZEVENT"
+	redelivered := event
+	redelivered.IsRedelivery = true
+
+	// Act
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{redelivered}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Assert
+	if chatCalls != 1 {
+		t.Errorf("Expected 1 LM Studio call, got %d", chatCalls)
+	}
+	if replyCalls != 1 {
+		t.Errorf("Expected 1 reply, got %d", replyCalls)
+	}
+}
+
+// TestEventDedup_EventsWithoutIDAreProcessed tests that events lacking an ID bypass the dedup store
+func TestEventDedup_EventsWithoutIDAreProcessed(t *testing.T) {
+	// Arrange
+	chatCalls := 0
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			chatCalls++
+			return &domain.ChatCompletionResponse{Content: "AI response"}, nil
+		},
+	}
+	dedupStore := &MockEventDedupStore{
+		MarkSeenFunc: func(eventID string) (bool, error) {
+			t.Errorf("Expected dedup store not to be called for events without ID")
+			return false, nil
+		},
+	}
+
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithEventDedup(dedupStore))
+
+	// Act
+	event := createTextMessageEvent("Hello")
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event, event}})
+
+	// Assert
+	if chatCalls != 2 {
+		t.Errorf("Expected 2 LM Studio calls, got %d", chatCalls)
+	}
+}
+
+// TestEventDedup_StoreErrorProcessesEvent tests that a dedup store failure does not drop the event
+func TestEventDedup_StoreErrorProcessesEvent(t *testing.T) {
+	// Arrange
+	chatCalls := 0
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			chatCalls++
+			return &domain.ChatCompletionResponse{Content: "AI response"}, nil
+		},
+	}
+	dedupStore := &MockEventDedupStore{
+		MarkSeenFunc: func(eventID string) (bool, error) {
+			return false, errors.New("database unavailable")
+		},
+	}
+
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithEventDedup(dedupStore))
+
+	event := createTextMessageEvent("Hello")
+	event.ID = "01HXYZEVENT"
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if chatCalls != 1 {
+		t.Errorf("Expected event to be processed despite store error, got %d calls", chatCalls)
+	}
+}
+
+// ============================================================================
+// Atomic Turn Append Tests
+// ============================================================================
+
+// MockAppendingSessionStore implements output.SessionStore and output.SessionTurnAppender for testing
+type MockAppendingSessionStore struct {
+	MockSessionStore
+
+	AppendCalls [][]domain.ChatMessage
+}
+
+func (m *MockAppendingSessionStore) AppendTurn(userID string, userMsg, assistantMsg domain.ChatMessage) error {
+	m.AppendCalls = append(m.AppendCalls, []domain.ChatMessage{userMsg, assistantMsg})
+	return nil
+}
+
+// TestStoreTurn_UsesAtomicAppendWhenSupported tests that stores implementing SessionTurnAppender
+// receive the turn via AppendTurn instead of a read-modify-write
+func TestStoreTurn_UsesAtomicAppendWhenSupported(t *testing.T) {
+	// Arrange
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			return &domain.ChatCompletionResponse{Content: "Hello! How can I help you?"}, nil
+		},
+	}
+	store := &MockAppendingSessionStore{}
+
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, store, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi there!")}})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if len(store.AppendCalls) != 1 {
+		t.Fatalf("Expected 1 AppendTurn call, got %d", len(store.AppendCalls))
+	}
+	if len(store.UpdateCalls) != 0 {
+		t.Errorf("Expected UpdateSession not to be called, got %d calls", len(store.UpdateCalls))
+	}
+	turn := store.AppendCalls[0]
+	if turn[0].Content != "Hi there!" || turn[1].Content != "Hello! How can I help you?" {
+		t.Errorf("Expected appended turn to contain user and assistant messages, got %+v", turn)
+	}
+}
+
+// ============================================================================
+// Rate Limiting Tests
+// ============================================================================
+
+// MockRateLimiter implements output.RateLimiter for testing
+type MockRateLimiter struct {
+	AllowFunc func(key string) (bool, error)
+
+	Keys []string
+}
+
+func (m *MockRateLimiter) Allow(key string) (bool, error) {
+	m.Keys = append(m.Keys, key)
+	if m.AllowFunc != nil {
+		return m.AllowFunc(key)
+	}
+	return true, nil
+}
+
+// TestRateLimit_ExceededRepliesSlowDownWithoutCallingLMStudio tests that a limited user gets
+// the friendly slow down reply and no LM Studio call is made
+func TestRateLimit_ExceededRepliesSlowDownWithoutCallingLMStudio(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	chatCalls := 0
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			chatCalls++
+			return &domain.ChatCompletionResponse{Content: "AI response"}, nil
+		},
+	}
+	limiter := &MockRateLimiter{
+		AllowFunc: func(key string) (bool, error) { return false, nil },
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithRateLimiter(limiter))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hello")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if chatCalls != 0 {
+		t.Errorf("Expected no LM Studio calls, got %d", chatCalls)
+	}
+	if len(limiter.Keys) != 1 || limiter.Keys[0] != "test-user-id" {
+		t.Errorf("Expected rate limit keyed by user ID, got %v", limiter.Keys)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != rateLimitMessage {
+		t.Errorf("Expected slow down reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
+
+// TestRateLimit_CommandsAreNotLimited tests that commands bypass the rate limiter
+func TestRateLimit_CommandsAreNotLimited(t *testing.T) {
+	// Arrange
+	limiter := &MockRateLimiter{
+		AllowFunc: func(key string) (bool, error) { return false, nil },
+	}
+	mockLineClient := &MockLineClient{}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithRateLimiter(limiter))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("/help")}})
+
+	// Assert
+	if len(limiter.Keys) != 0 {
+		t.Errorf("Expected commands not to consume rate limit, got %v", limiter.Keys)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text == rateLimitMessage {
+		t.Error("Expected the help reply, not the slow down message")
+	}
+}
+
+// TestRateLimit_LimiterErrorAllowsRequest tests that a failing limiter does not block users
+func TestRateLimit_LimiterErrorAllowsRequest(t *testing.T) {
+	// Arrange
+	limiter := &MockRateLimiter{
+		AllowFunc: func(key string) (bool, error) { return false, errors.New("limiter down") },
+	}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithRateLimiter(limiter))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hello")}})
+
+	// Assert
+	if mockLMStudioClient.LastChatRequest == nil {
+		t.Error("Expected LM Studio to be called when the limiter fails")
+	}
+}
+
+// TestErrorHandling_LMStudioBusy tests that a busy LM Studio gets its own friendly message
+func TestErrorHandling_LMStudioBusy(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			return nil, fmt.Errorf("%w: no free slot", domain.ErrLMStudioBusy)
+		},
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hello")}})
+
+	// Assert
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != lmStudioBusyMessage {
+		t.Errorf("Expected busy message, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"strings"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StickerPolicy - How incoming stickers are answered
+type StickerPolicy string
+
+const (
+	// StickerPolicyIgnore - Stickers get no reply
+	StickerPolicyIgnore StickerPolicy = "ignore"
+	// StickerPolicyLLM - The sticker's keywords are sent to LM Studio as the user's message
+	StickerPolicyLLM StickerPolicy = "llm"
+	// StickerPolicyReply - A sticker from the configured reply table is sent back
+	StickerPolicyReply StickerPolicy = "reply"
+)
+
+// stickerReplyWildcard matches any sticker in the reply table
+const stickerReplyWildcard = "*"
+
+// StickerReply struct - Sticker sent back when an incoming sticker has a matching keyword
+type StickerReply struct {
+	Keyword   string // Matched case-insensitively against the sticker's keywords, "*" matches any sticker
+	PackageID string
+	StickerID string
+}
+
+// WithStickerPolicy func - Sets how incoming stickers are answered
+// replies is only used by StickerPolicyReply; entries are matched in order.
+func WithStickerPolicy(policy StickerPolicy, replies []StickerReply) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.stickerPolicy = policy
+		s.stickerReplies = replies
+	}
+}
+
+// answersStickers - Helper method to check whether incoming stickers get a reply
+func (s *LineWebhookService) answersStickers() bool {
+	return s.stickerPolicy == StickerPolicyLLM || s.stickerPolicy == StickerPolicyReply
+}
+
+// replyWithSticker - Helper method to answer a sticker with the first matching sticker from the reply table
+// Stickers without a matching entry get no reply.
+func (s *LineWebhookService) replyWithSticker(event domain.LineWebhookEvent) error {
+	reply, ok := matchStickerReply(s.stickerReplies, event.Message.Keywords)
+	if !ok {
+		logrus.Infof("No sticker reply for keywords %v", event.Message.Keywords)
+		return nil
+	}
+
+	if event.ReplyToken == "" {
+		return nil
+	}
+
+	replyReq := domain.LineReplyMessageRequest{
+		ReplyToken: event.ReplyToken,
+		Messages: []domain.LineOutgoingMessage{
+			{
+				Type:      domain.LineMessageTypeSticker,
+				PackageID: reply.PackageID,
+				StickerID: reply.StickerID,
+			},
+		},
+	}
+
+	_, err := s.lineClient.ReplyMessage(replyReq)
+	return err
+}
+
+// matchStickerReply - Helper to find the first reply whose keyword matches one of the sticker's keywords
+func matchStickerReply(replies []StickerReply, keywords []string) (StickerReply, bool) {
+	for _, reply := range replies {
+		if reply.Keyword == stickerReplyWildcard {
+			return reply, true
+		}
+		for _, keyword := range keywords {
+			if strings.EqualFold(reply.Keyword, keyword) {
+				return reply, true
+			}
+		}
+	}
+	return StickerReply{}, false
+}
+
+// formatStickerMessage - Helper to describe a sticker as the user's message for LM Studio
+func formatStickerMessage(message *domain.LineMessage) string {
+	if len(message.Keywords) == 0 {
+		return "[I sent you a sticker]"
+	}
+	return "[I sent you a sticker expressing: " + strings.Join(message.Keywords, ", ") + "]"
+}
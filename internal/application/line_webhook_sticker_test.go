@@ -0,0 +1,127 @@
+package application
+
+import (
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// createStickerMessageEvent creates a sticker message event from the test user
+func createStickerMessageEvent(keywords ...string) domain.LineWebhookEvent {
+	event := createTextMessageEvent("")
+	event.Message.Type = domain.LineMessageTypeSticker
+	event.Message.PackageID = "446"
+	event.Message.StickerID = "1988"
+	event.Message.Keywords = keywords
+	return event
+}
+
+// testStickerReplies is a reply table with a keyword entry and a wildcard fallback
+var testStickerReplies = []StickerReply{
+	{Keyword: "thanks", PackageID: "11537", StickerID: "52002734"},
+	{Keyword: "*", PackageID: "11537", StickerID: "52002736"},
+}
+
+// TestSticker_IgnoredByDefault tests that stickers get no reply when no sticker policy is set
+func TestSticker_IgnoredByDefault(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns)
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createStickerMessageEvent("Happy")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLMStudioClient.LastChatRequest != nil || mockLineClient.LastReplyRequest != nil {
+		t.Error("Expected sticker to be ignored")
+	}
+}
+
+// TestSticker_LLMPolicySendsKeywords tests that the LLM policy sends the sticker's keywords to LM Studio
+func TestSticker_LLMPolicySendsKeywords(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithStickerPolicy(StickerPolicyLLM, nil))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createStickerMessageEvent("Happy", "Smile")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	messages := mockLMStudioClient.LastChatRequest.Messages
+	if got := messages[len(messages)-1].Content; got != "[I sent you a sticker expressing: Happy, Smile]" {
+		t.Errorf("Expected sticker keywords as user message, got %q", got)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "AI response" {
+		t.Errorf("Expected AI reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
+
+// TestSticker_ReplyPolicyMatchesTable tests that the reply policy answers with the first matching
+// sticker from the table, falling back to the wildcard entry, without calling LM Studio
+func TestSticker_ReplyPolicyMatchesTable(t *testing.T) {
+	tests := []struct {
+		name              string
+		keywords          []string
+		expectedStickerID string
+	}{
+		{"keyword match is case-insensitive", []string{"Love", "Thanks"}, "52002734"},
+		{"wildcard fallback", []string{"Sleepy"}, "52002736"},
+		{"no keywords uses wildcard", nil, "52002736"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockLineClient := &MockLineClient{}
+			mockLMStudioClient := &MockLMStudioClient{}
+			service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+				WithStickerPolicy(StickerPolicyReply, testStickerReplies))
+
+			// Act
+			err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createStickerMessageEvent(tt.keywords...)}})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if mockLMStudioClient.LastChatRequest != nil {
+				t.Error("Expected LM Studio not to be called")
+			}
+			reply := mockLineClient.LastReplyRequest
+			if reply == nil || reply.Messages[0].Type != domain.LineMessageTypeSticker {
+				t.Fatalf("Expected sticker reply, got %+v", reply)
+			}
+			if reply.Messages[0].PackageID != "11537" || reply.Messages[0].StickerID != tt.expectedStickerID {
+				t.Errorf("Expected sticker 11537/%s, got %+v", tt.expectedStickerID, reply.Messages[0])
+			}
+		})
+	}
+}
+
+// TestSticker_ReplyPolicyWithoutMatch tests that a sticker without a matching table entry gets no reply
+func TestSticker_ReplyPolicyWithoutMatch(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	service := NewLineWebhookService(mockLineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithStickerPolicy(StickerPolicyReply, testStickerReplies[:1]))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createStickerMessageEvent("Sleepy")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest != nil {
+		t.Errorf("Expected no reply, got %+v", mockLineClient.LastReplyRequest)
+	}
+}
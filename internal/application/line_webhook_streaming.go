@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamReplier - Delivers streamed response segments to a LINE user
+// The first segment is sent via ReplyMessage, subsequent segments via PushMessage.
+type streamReplier struct {
+	service *LineWebhookService
+	event   domain.LineWebhookEvent
+	sent    int
+}
+
+// send - Sends a single segment, returning an error only if the reply message fails
+func (r *streamReplier) send(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	message := []domain.LineOutgoingMessage{
+		{
+			Type: domain.LineMessageTypeText,
+			Text: text,
+		},
+	}
+
+	if r.sent == 0 {
+		r.sent++
+		if r.event.ReplyToken == "" {
+			return nil
+		}
+
+		replyReq := domain.LineReplyMessageRequest{
+			ReplyToken: r.event.ReplyToken,
+			Messages:   message,
+		}
+		if _, err := r.service.lineClient.ReplyMessage(replyReq); err != nil {
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+		return nil
+	}
+
+	pushReq := domain.LinePushMessageRequest{
+		To:       conversationKey(r.event.Source),
+		Messages: message,
+	}
+	if _, err := r.service.lineClient.PushMessage(pushReq); err != nil {
+		logrus.Errorf("Failed to send push message %d: %v", r.sent, err)
+		// Continue sending remaining segments even if one fails
+	}
+	r.sent++
+	return nil
+}
+
+// canSendMore - Reports whether another segment may be sent before the final flush
+// The last available message slot is reserved for whatever remains at the end of the stream.
+func (r *streamReplier) canSendMore() bool {
+	return r.sent < maxMessagesPerResponse-1
+}
+
+// handleStreamingResponse - Business logic for delivering an AI response progressively
+// Consumes ChatCompletionStream chunks, sends the first sentence-bounded segment via the
+// reply token as soon as it is ready, and pushes subsequent segments as they accumulate.
+// The complete response is stored in the session only if the stream finishes without error.
+func (s *LineWebhookService) handleStreamingResponse(event domain.LineWebhookEvent, userText string, chatRequest domain.ChatCompletionRequest) error {
+	chatRequest.Stream = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chunks, err := s.lmStudioClient.ChatCompletionStream(ctx, chatRequest)
+	if err == nil && chunks == nil {
+		err = errors.New("streaming chat completion returned no channel")
+	}
+	if err != nil {
+		logLMStudioError(err)
+		replier := &streamReplier{service: s, event: event}
+		return replier.send(lmStudioUserMessage(err))
+	}
+
+	// Drain the channel on exit so the producer goroutine is never left blocked
+	defer func() {
+		cancel()
+		for range chunks {
+		}
+	}()
+
+	replier := &streamReplier{service: s, event: event}
+	var fullContent strings.Builder
+	var pending string
+	var streamErr error
+
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			break
+		}
+
+		fullContent.WriteString(chunk.Content)
+		pending += chunk.Content
+
+		if chunk.Done {
+			break
+		}
+
+		// Send every complete segment that is ready
+		for replier.canSendMore() {
+			minLength := streamSegmentMinLength
+			if replier.sent == 0 {
+				minLength = streamFirstSegmentMinLength
+			}
+
+			segment, rest, ok := s.extractStreamSegment(pending, minLength)
+			if !ok {
+				break
+			}
+
+			if err := replier.send(segment); err != nil {
+				return err
+			}
+			pending = rest
+		}
+	}
+
+	if streamErr != nil {
+		logLMStudioError(streamErr)
+
+		// Nothing delivered yet - the user gets the same friendly message as the blocking path
+		if replier.sent == 0 {
+			return replier.send(lmStudioUserMessage(streamErr))
+		}
+	}
+
+	// Flush whatever is left, respecting the LINE message length and count limits
+	remaining := strings.TrimSpace(pending)
+	if remaining != "" {
+		for _, part := range s.splitAIResponse(remaining) {
+			if replier.sent >= maxMessagesPerResponse {
+				logrus.Warnf("Streaming response exceeded %d messages, dropping remaining content", maxMessagesPerResponse)
+				break
+			}
+			if err := replier.send(part); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Store conversation turn in session (only on success)
+	if streamErr == nil {
+		s.storeTurn(conversationKey(event.Source), userText, fullContent.String())
+	}
+
+	return nil
+}
+
+// extractStreamSegment - Helper method to cut a sendable segment from the head of a streaming buffer
+// Returns the text up to the latest sentence boundary (or newline) found at or after minLength,
+// and the rest of the buffer. A boundary at the very end of the buffer is not used because the
+// next chunk may continue it (e.g. "3." followed by "14"). If the buffer already exceeds LINE's
+// message length, it is split the same way as splitAIResponse.
+func (s *LineWebhookService) extractStreamSegment(buffer string, minLength int) (string, string, bool) {
+	if len(buffer) > maxLineMessageLength {
+		splitPoint := s.findSentenceBoundary(buffer, maxLineMessageLength)
+		return buffer[:splitPoint], strings.TrimLeft(buffer[splitPoint:], " "), true
+	}
+
+	searchStart := minLength - 1
+	if searchStart < 0 {
+		searchStart = 0
+	}
+
+	for i := len(buffer) - 2; i >= searchStart; i-- {
+		if buffer[i] == '\n' || isSentenceEnd(buffer, i) {
+			return buffer[:i+1], strings.TrimLeft(buffer[i+1:], " \n"), true
+		}
+	}
+
+	return "", buffer, false
+}
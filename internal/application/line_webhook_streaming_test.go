@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// streamOf returns a closed channel pre-filled with the given chunks
+func streamOf(chunks ...domain.ChatCompletionChunk) <-chan domain.ChatCompletionChunk {
+	ch := make(chan domain.ChatCompletionChunk, len(chunks))
+	for _, chunk := range chunks {
+		ch <- chunk
+	}
+	close(ch)
+	return ch
+}
+
+// TestStreaming_FirstSentenceRepliedRestPushed tests that the first sentence goes via ReplyMessage
+// and later content goes via PushMessage
+func TestStreaming_FirstSentenceRepliedRestPushed(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	secondPart := strings.Repeat("More detail here. ", 15)
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			if !request.Stream {
+				t.Error("Expected streaming request to have Stream=true")
+			}
+			return streamOf(
+				domain.ChatCompletionChunk{Content: "Hello there"},
+				domain.ChatCompletionChunk{Content: "! How are"},
+				domain.ChatCompletionChunk{Content: " you? " + secondPart},
+				domain.ChatCompletionChunk{Content: "The end."},
+				domain.ChatCompletionChunk{Done: true},
+			), nil
+		},
+	}
+	mockSessionStore := &MockSessionStore{}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithStreaming(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if mockLMStudioClient.LastChatRequest != nil {
+		t.Error("Expected blocking ChatCompletion not to be called in streaming mode")
+	}
+
+	if mockLineClient.LastReplyRequest == nil {
+		t.Fatal("Expected ReplyMessage to be called")
+	}
+	if got := mockLineClient.LastReplyRequest.Messages[0].Text; got != "Hello there!" {
+		t.Errorf("Expected first reply to be the first sentence, got: %q", got)
+	}
+
+	if len(mockLineClient.PushRequests) == 0 {
+		t.Fatal("Expected PushMessage to be called for subsequent segments")
+	}
+
+	var delivered []string
+	delivered = append(delivered, mockLineClient.LastReplyRequest.Messages[0].Text)
+	for _, push := range mockLineClient.PushRequests {
+		if push.To != "test-user-id" {
+			t.Errorf("Expected push to test-user-id, got %s", push.To)
+		}
+		delivered = append(delivered, push.Messages[0].Text)
+	}
+	if !strings.HasSuffix(delivered[len(delivered)-1], "The end.") {
+		t.Errorf("Expected last segment to end with 'The end.', got: %q", delivered[len(delivered)-1])
+	}
+
+	// Full response is stored in the session
+	if mockSessionStore.LastUpdatedSession == nil {
+		t.Fatal("Expected session to be updated")
+	}
+	history := mockSessionStore.LastUpdatedSession.GetHistory()
+	want := "Hello there! How are you? " + secondPart + "The end."
+	if history[1].Content != want {
+		t.Errorf("Expected stored assistant message to be full response, got: %q", history[1].Content)
+	}
+}
+
+// TestStreaming_ShortResponseSingleReply tests that a short response without a trailing boundary
+// is flushed as a single reply when the stream completes
+func TestStreaming_ShortResponseSingleReply(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return streamOf(
+				domain.ChatCompletionChunk{Content: "Pi is 3."},
+				domain.ChatCompletionChunk{Content: "14"},
+				domain.ChatCompletionChunk{Done: true},
+			), nil
+		},
+	}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithStreaming(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("What is pi?")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "Pi is 3.14" {
+		t.Errorf("Expected single reply 'Pi is 3.14', got: %+v", mockLineClient.LastReplyRequest)
+	}
+	if len(mockLineClient.PushRequests) != 0 {
+		t.Errorf("Expected no push messages, got %d", len(mockLineClient.PushRequests))
+	}
+}
+
+// TestStreaming_ErrorBeforeStreamSendsFriendlyMessage tests that a failed stream start
+// replies with the user-friendly error and does not store the turn
+func TestStreaming_ErrorBeforeStreamSendsFriendlyMessage(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return nil, domain.ErrLMStudioUnavailable
+		},
+	}
+	mockSessionStore := &MockSessionStore{}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithStreaming(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != lmStudioErrorMessage {
+		t.Errorf("Expected friendly error reply, got: %+v", mockLineClient.LastReplyRequest)
+	}
+	if len(mockSessionStore.UpdateCalls) != 0 {
+		t.Errorf("Expected no session updates on error, got %d", len(mockSessionStore.UpdateCalls))
+	}
+}
+
+// TestStreaming_MidStreamErrorKeepsDeliveredContent tests that an error after the first segment
+// flushes buffered text and does not store a partial turn
+func TestStreaming_MidStreamErrorKeepsDeliveredContent(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return streamOf(
+				domain.ChatCompletionChunk{Content: "First sentence. Second"},
+				domain.ChatCompletionChunk{Done: true, Error: errors.New("connection reset")},
+			), nil
+		},
+	}
+	mockSessionStore := &MockSessionStore{}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithStreaming(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if mockLineClient.LastReplyRequest == nil || mockLineClient.LastReplyRequest.Messages[0].Text != "First sentence." {
+		t.Errorf("Expected first sentence as reply, got: %+v", mockLineClient.LastReplyRequest)
+	}
+	if len(mockLineClient.PushRequests) != 1 || mockLineClient.PushRequests[0].Messages[0].Text != "Second" {
+		t.Errorf("Expected buffered text to be pushed, got: %+v", mockLineClient.PushRequests)
+	}
+	if len(mockSessionStore.UpdateCalls) != 0 {
+		t.Errorf("Expected no session updates for partial response, got %d", len(mockSessionStore.UpdateCalls))
+	}
+}
+
+// TestStreaming_RespectsMaxMessages tests that streaming never sends more than maxMessagesPerResponse messages
+func TestStreaming_RespectsMaxMessages(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	var chunks []domain.ChatCompletionChunk
+	for i := 0; i < 50; i++ {
+		chunks = append(chunks, domain.ChatCompletionChunk{Content: strings.Repeat("word ", 50) + "done. "})
+	}
+	chunks = append(chunks, domain.ChatCompletionChunk{Done: true})
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return streamOf(chunks...), nil
+		},
+	}
+
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns, WithStreaming(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	total := len(mockLineClient.PushRequests) + 1
+	if total > maxMessagesPerResponse {
+		t.Errorf("Expected at most %d messages, got %d", maxMessagesPerResponse, total)
+	}
+	for i, push := range mockLineClient.PushRequests {
+		if len(push.Messages[0].Text) > maxLineMessageLength {
+			t.Errorf("Push %d exceeds max length: %d", i, len(push.Messages[0].Text))
+		}
+	}
+}
+
+// TestExtractStreamSegment tests segment extraction from a streaming buffer
+func TestExtractStreamSegment(t *testing.T) {
+	service := &LineWebhookService{}
+
+	tests := []struct {
+		name      string
+		buffer    string
+		minLength int
+		segment   string
+		rest      string
+		ok        bool
+	}{
+		{"no boundary", "Hello there", 1, "", "Hello there", false},
+		{"boundary at end is held back", "Hello there.", 1, "", "Hello there.", false},
+		{"latest boundary used", "One. Two! Three", 1, "One. Two!", "Three", true},
+		{"newline boundary", "Line one\nLine two", 1, "Line one\n", "Line two", true},
+		{"below minimum length", "One. Two", 10, "", "One. Two", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segment, rest, ok := service.extractStreamSegment(tt.buffer, tt.minLength)
+			if ok != tt.ok || segment != tt.segment || rest != tt.rest {
+				t.Errorf("extractStreamSegment(%q, %d) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.buffer, tt.minLength, segment, rest, ok, tt.segment, tt.rest, tt.ok)
+			}
+		})
+	}
+}
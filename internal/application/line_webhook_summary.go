@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// summaryPrompt instructs the model how to fold overflowed turns into the rolling summary
+const summaryPrompt = "You maintain a running summary of a chat between a user and an assistant. " +
+	"Merge the previous summary with the new conversation excerpt into one updated summary. " +
+	"Keep facts, names, preferences, open questions, and commitments the assistant made. " +
+	"Write in the user's language, in plain prose, in at most 150 words. Reply with the summary only."
+
+// summarizeOverflow - Helper method to fold the oldest turns of a full session into its rolling summary
+// Half of the history is summarized at once so a summary call is not needed on every turn.
+// If the summary call fails, the session is left unchanged and AddTurn drops the oldest turn as usual.
+func (s *LineWebhookService) summarizeOverflow(session *domain.ConversationSession) {
+	turns := session.TurnCount() / 2
+	if turns < 1 {
+		turns = 1
+	}
+
+	// Work on a copy so a failed summary leaves the session untouched
+	candidate := *session
+	candidate.Messages = session.GetHistory()
+	overflow := candidate.TakeOldestTurns(turns)
+
+	summary, err := s.summarizeTurns(session.Summary(), overflow)
+	if err != nil {
+		logrus.Warnf("Failed to summarize history for user %s, dropping oldest turn instead: %v", session.UserID, err)
+		return
+	}
+
+	candidate.SetSummary(summary)
+	session.Messages = candidate.Messages
+	logrus.Infof("Summarized %d turns into the session summary for user %s", turns, session.UserID)
+}
+
+// summarizeTurns - Helper method to request an updated summary from LM Studio
+func (s *LineWebhookService) summarizeTurns(previousSummary string, messages []domain.ChatMessage) (string, error) {
+	var excerpt strings.Builder
+	if previousSummary != "" {
+		excerpt.WriteString("Previous summary:\n")
+		excerpt.WriteString(previousSummary)
+		excerpt.WriteString("\n\n")
+	}
+	excerpt.WriteString("New conversation excerpt:\n")
+	for _, msg := range messages {
+		speaker := "User"
+		if msg.Role == domain.ChatMessageRoleAssistant {
+			speaker = "Assistant"
+		}
+		fmt.Fprintf(&excerpt, "%s: %s\n", speaker, msg.Content)
+	}
+
+	request := domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{
+			{Role: domain.ChatMessageRoleSystem, Content: summaryPrompt},
+			{Role: domain.ChatMessageRoleUser, Content: excerpt.String()},
+		},
+	}
+	if model := s.currentModel(); model != "" {
+		request.Model = &model
+	}
+
+	response, err := s.lmStudioClient.ChatCompletion(context.Background(), request)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(response.Content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+
+	return summary, nil
+}
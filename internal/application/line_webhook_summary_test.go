@@ -0,0 +1,196 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// fullSession creates a session for the test user holding maxTurns turns Q1/A1..Qn/An
+func fullSession(maxTurns int) *domain.ConversationSession {
+	session := domain.NewConversationSession("test-user-id", defaultTestTimeout, maxTurns)
+	for i := 1; i <= maxTurns; i++ {
+		session.AddTurn(
+			domain.ChatMessage{Role: domain.ChatMessageRoleUser, Content: fmt.Sprintf("Q%d", i)},
+			domain.ChatMessage{Role: domain.ChatMessageRoleAssistant, Content: fmt.Sprintf("A%d", i)},
+		)
+	}
+	return session
+}
+
+// summarizingLMStudioClient returns a mock answering chat requests with "reply" and
+// summary requests with summaryFunc, recording the summary requests it receives
+func summarizingLMStudioClient(summaryRequests *[]domain.ChatCompletionRequest, summaryFunc func() (string, error)) *MockLMStudioClient {
+	return &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			if request.Messages[0].Content == summaryPrompt {
+				*summaryRequests = append(*summaryRequests, request)
+				summary, err := summaryFunc()
+				if err != nil {
+					return nil, err
+				}
+				return &domain.ChatCompletionResponse{Content: summary}, nil
+			}
+			return &domain.ChatCompletionResponse{Content: "reply"}, nil
+		},
+	}
+}
+
+// TestSummarization_OverflowedTurnsFoldedIntoSummary tests that a full session has its oldest
+// turns summarized instead of discarded
+func TestSummarization_OverflowedTurnsFoldedIntoSummary(t *testing.T) {
+	// Arrange
+	var summaryRequests []domain.ChatCompletionRequest
+	mockLMStudioClient := summarizingLMStudioClient(&summaryRequests, func() (string, error) {
+		return "User asked Q1 and Q2.", nil
+	})
+	session := fullSession(4)
+	mockSessionStore := &MockSessionStore{
+		GetSessionFunc: func(userID string) (*domain.ConversationSession, error) {
+			return session, nil
+		},
+	}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, 4,
+		WithSummarization(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Q5")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(summaryRequests) != 1 {
+		t.Fatalf("Expected 1 summary request, got %d", len(summaryRequests))
+	}
+	excerpt := summaryRequests[0].Messages[1].Content
+	if !strings.Contains(excerpt, "User: Q1") || !strings.Contains(excerpt, "Assistant: A2") || strings.Contains(excerpt, "Q3") {
+		t.Errorf("Expected the two oldest turns to be summarized, got excerpt: %s", excerpt)
+	}
+
+	stored := mockSessionStore.LastUpdatedSession
+	if stored == nil {
+		t.Fatal("Expected session to be updated")
+	}
+	if stored.Summary() != "User asked Q1 and Q2." {
+		t.Errorf("Expected summary to be stored, got %q", stored.Summary())
+	}
+	if stored.Messages[0].Role != domain.ChatMessageRoleSystem {
+		t.Errorf("Expected summary to be the first history message, got role %s", stored.Messages[0].Role)
+	}
+	if stored.TurnCount() != 3 || stored.Messages[1].Content != "Q3" {
+		t.Errorf("Expected turns Q3, Q4, Q5 after the summary, got %+v", stored.Messages)
+	}
+}
+
+// TestSummarization_PreviousSummaryIsMerged tests that the previous summary is sent along with the overflow
+func TestSummarization_PreviousSummaryIsMerged(t *testing.T) {
+	// Arrange
+	var summaryRequests []domain.ChatCompletionRequest
+	mockLMStudioClient := summarizingLMStudioClient(&summaryRequests, func() (string, error) {
+		return "Updated summary.", nil
+	})
+	session := fullSession(2)
+	session.SetSummary("User is called Somchai.")
+	mockSessionStore := &MockSessionStore{
+		GetSessionFunc: func(userID string) (*domain.ConversationSession, error) {
+			return session, nil
+		},
+	}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, 2,
+		WithSummarization(true))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Q3")}})
+
+	// Assert
+	if len(summaryRequests) != 1 {
+		t.Fatalf("Expected 1 summary request, got %d", len(summaryRequests))
+	}
+	if !strings.Contains(summaryRequests[0].Messages[1].Content, "User is called Somchai.") {
+		t.Errorf("Expected previous summary in the summary request, got: %s", summaryRequests[0].Messages[1].Content)
+	}
+	if mockSessionStore.LastUpdatedSession.Summary() != "Updated summary." {
+		t.Errorf("Expected summary to be replaced, got %q", mockSessionStore.LastUpdatedSession.Summary())
+	}
+}
+
+// TestSummarization_FailureFallsBackToDroppingOldestTurn tests that a failed summary call
+// leaves the previous trimming behaviour in place
+func TestSummarization_FailureFallsBackToDroppingOldestTurn(t *testing.T) {
+	// Arrange
+	var summaryRequests []domain.ChatCompletionRequest
+	mockLMStudioClient := summarizingLMStudioClient(&summaryRequests, func() (string, error) {
+		return "", domain.ErrLMStudioUnavailable
+	})
+	session := fullSession(4)
+	mockSessionStore := &MockSessionStore{
+		GetSessionFunc: func(userID string) (*domain.ConversationSession, error) {
+			return session, nil
+		},
+	}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, 4,
+		WithSummarization(true))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Q5")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stored := mockSessionStore.LastUpdatedSession
+	if stored.Summary() != "" {
+		t.Errorf("Expected no summary, got %q", stored.Summary())
+	}
+	if stored.TurnCount() != 4 || stored.Messages[0].Content != "Q2" {
+		t.Errorf("Expected oldest turn to be dropped, got %+v", stored.Messages)
+	}
+}
+
+// TestSummarization_NotTriggeredBeforeHistoryIsFull tests that no summary call is made while there is room
+func TestSummarization_NotTriggeredBeforeHistoryIsFull(t *testing.T) {
+	// Arrange
+	var summaryRequests []domain.ChatCompletionRequest
+	mockLMStudioClient := summarizingLMStudioClient(&summaryRequests, func() (string, error) {
+		return "", errors.New("unexpected summary call")
+	})
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithSummarization(true))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if len(summaryRequests) != 0 {
+		t.Errorf("Expected no summary requests, got %d", len(summaryRequests))
+	}
+}
+
+// TestSummarization_BypassesAtomicAppend tests that stores supporting AppendTurn are updated
+// via UpdateSession so overflowed turns can be summarized
+func TestSummarization_BypassesAtomicAppend(t *testing.T) {
+	// Arrange
+	var summaryRequests []domain.ChatCompletionRequest
+	mockLMStudioClient := summarizingLMStudioClient(&summaryRequests, func() (string, error) {
+		return "summary", nil
+	})
+	store := &MockAppendingSessionStore{}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, store, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithSummarization(true))
+
+	// Act
+	_ = service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi")}})
+
+	// Assert
+	if len(store.AppendCalls) != 0 {
+		t.Errorf("Expected AppendTurn not to be used, got %d calls", len(store.AppendCalls))
+	}
+	if len(store.UpdateCalls) != 1 {
+		t.Errorf("Expected 1 UpdateSession call, got %d", len(store.UpdateCalls))
+	}
+}
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/input"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Usage shown for /todo without a valid subcommand
+const todoUsageMessage = "Usage:\n/todo add <title> - Add a todo\n/todo list - List your todos\n/todo done <number> - Mark a todo as done\n/todo delete <number> - Delete a todo"
+
+// User-friendly message when the todo store fails
+const todoErrorMessage = "Sorry, I couldn't reach your todo list right now. Please try again later."
+
+// User-friendly message when LINE did not share the sender's user ID
+const todoOwnerUnknownMessage = "Sorry, I can't tell who you are in this chat. Add me as a friend to use /todo."
+
+// Maximum number of todos shown by /todo list; numbers in /todo done and /todo delete refer to this list
+const maxTodoCommandItems = 30
+
+// WithTodoService func - Enables the /todo command backed by the todo use cases
+// Todos are scoped to the LINE user who sends the command.
+func WithTodoService(todoService input.TodoService) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		if todoService == nil {
+			return
+		}
+		s.todoService = todoService
+		err := s.commands.Register(Command{
+			Name:        "todo",
+			Description: "Manage your todos",
+			Handler: func(cmd CommandContext) []domain.LineOutgoingMessage {
+				return s.handleTodoCommand(cmd.Args, cmd.UserID)
+			},
+		})
+		if err != nil {
+			logrus.Errorf("Failed to register command /todo: %v", err)
+		}
+	}
+}
+
+// handleTodoCommand - Business logic for /todo add, list, done, and delete
+// args are the words after "/todo"; userID is the LINE user the todos belong to.
+func (s *LineWebhookService) handleTodoCommand(args []string, userID string) []domain.LineOutgoingMessage {
+	if userID == "" {
+		return textMessages(todoOwnerUnknownMessage)
+	}
+	if len(args) == 0 {
+		return textMessages(todoUsageMessage)
+	}
+
+	subcommand := strings.ToLower(args[0])
+	argument := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	switch subcommand {
+	case "add":
+		return s.addTodo(argument, userID)
+	case "list":
+		return s.listTodos(userID)
+	case "done":
+		return s.completeTodo(argument, userID)
+	case "delete":
+		return s.deleteTodo(argument, userID)
+	default:
+		return textMessages(todoUsageMessage)
+	}
+}
+
+// addTodo - Helper method to create an in-progress todo due now for the user
+func (s *LineWebhookService) addTodo(title, userID string) []domain.LineOutgoingMessage {
+	if title == "" {
+		return textMessages("Usage: /todo add <title>")
+	}
+	if len(title) > maxTodoTitleLength {
+		return textMessages(fmt.Sprintf("Todo titles can be at most %d characters.", maxTodoTitleLength))
+	}
+
+	date := time.Now().UTC().Format(domain.DatetimeLayout)
+	status := domain.TodoStatusInProgress
+	_, err := s.todoService.CreateTodo(domain.TodoRequest{
+		Title:   &title,
+		Date:    &date,
+		Status:  &status,
+		OwnerID: &userID,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to create todo for %s: %v", userID, err)
+		return textMessages(todoErrorMessage)
+	}
+
+	return textMessages("Added: " + title)
+}
+
+// listTodos - Helper method to list the user's todos, numbered for /todo done and /todo delete
+func (s *LineWebhookService) listTodos(userID string) []domain.LineOutgoingMessage {
+	todos, err := s.ownTodos(userID)
+	if err != nil {
+		logrus.Errorf("Failed to list todos for %s: %v", userID, err)
+		return textMessages(todoErrorMessage)
+	}
+	if len(todos) == 0 {
+		return textMessages("You have no todos. Add one with /todo add <title>")
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Your todos:")
+	for i, todo := range todos {
+		mark := " "
+		if todo.Status != nil && *todo.Status == domain.TodoStatusComplete {
+			mark = "x"
+		}
+		fmt.Fprintf(&builder, "\n%d. [%s] %s", i+1, mark, todoTitle(todo))
+	}
+
+	return textMessages(builder.String())
+}
+
+// completeTodo - Helper method to mark one of the user's todos as complete
+func (s *LineWebhookService) completeTodo(reference, userID string) []domain.LineOutgoingMessage {
+	todo, reply := s.findOwnTodo(reference, userID, "done")
+	if todo == nil {
+		return reply
+	}
+
+	status := domain.TodoStatusComplete
+	if _, err := s.todoService.UpdateTodo(domain.TodoRequest{ID: todo.ID, Status: &status, OwnerID: &userID}); err != nil {
+		logrus.Errorf("Failed to complete todo %s for %s: %v", todo.ID, userID, err)
+		return textMessages(todoErrorMessage)
+	}
+
+	return textMessages("Done: " + todoTitle(*todo))
+}
+
+// deleteTodo - Helper method to delete one of the user's todos
+func (s *LineWebhookService) deleteTodo(reference, userID string) []domain.LineOutgoingMessage {
+	todo, reply := s.findOwnTodo(reference, userID, "delete")
+	if todo == nil {
+		return reply
+	}
+
+	if _, err := s.todoService.DeleteTodo(domain.TodoRequest{ID: todo.ID, OwnerID: &userID}); err != nil {
+		logrus.Errorf("Failed to delete todo %s for %s: %v", todo.ID, userID, err)
+		return textMessages(todoErrorMessage)
+	}
+
+	return textMessages("Deleted: " + todoTitle(*todo))
+}
+
+// findOwnTodo - Helper method to resolve a todo by its number in /todo list or its ID
+// Only the user's own todos are found. When no todo is returned, the reply explains why.
+func (s *LineWebhookService) findOwnTodo(reference, userID, subcommand string) (*domain.TodoResponse, []domain.LineOutgoingMessage) {
+	usage := textMessages(fmt.Sprintf("Usage: /todo %s <number from /todo list>", subcommand))
+	notFound := textMessages("Todo not found. Type /todo list to see your todos.")
+
+	if id, err := uuid.Parse(reference); err == nil {
+		list, err := s.todoService.GetTodo(domain.QueryTodoRequest{ID: &id, OwnerID: &userID})
+		if err != nil {
+			logrus.Errorf("Failed to get todo %s for %s: %v", id, userID, err)
+			return nil, textMessages(todoErrorMessage)
+		}
+		if len(list.Todos) == 0 {
+			return nil, notFound
+		}
+		return &list.Todos[0], nil
+	}
+
+	number, err := strconv.Atoi(reference)
+	if err != nil || number < 1 {
+		return nil, usage
+	}
+
+	todos, err := s.ownTodos(userID)
+	if err != nil {
+		logrus.Errorf("Failed to list todos for %s: %v", userID, err)
+		return nil, textMessages(todoErrorMessage)
+	}
+	if number > len(todos) {
+		return nil, notFound
+	}
+	return &todos[number-1], nil
+}
+
+// ownTodos - Helper method to get the user's todos, oldest first so list numbers stay stable
+func (s *LineWebhookService) ownTodos(userID string) ([]domain.TodoResponse, error) {
+	limit := maxTodoCommandItems
+	orderBy := "created_at"
+	list, err := s.todoService.GetTodo(domain.QueryTodoRequest{
+		OwnerID: &userID,
+		Limit:   &limit,
+		OrderBy: &orderBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Todos, nil
+}
+
+// todoTitle - Helper to get a todo's title for display
+func todoTitle(todo domain.TodoResponse) string {
+	if todo.Title == nil {
+		return ""
+	}
+	return *todo.Title
+}
+
+// textMessages - Helper to build a reply consisting of a single text message
+func textMessages(text string) []domain.LineOutgoingMessage {
+	return []domain.LineOutgoingMessage{
+		{
+			Type: domain.LineMessageTypeText,
+			Text: text,
+		},
+	}
+}
@@ -0,0 +1,232 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// newTodoCommandService creates a service with the /todo command backed by todoService
+func newTodoCommandService(lineClient *MockLineClient, todoService *MockTodoService) *LineWebhookService {
+	return NewLineWebhookService(lineClient, &MockLMStudioClient{}, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithTodoService(todoService))
+}
+
+// sendTodoCommand sends text from the test user and returns the reply text
+func sendTodoCommand(t *testing.T, service *LineWebhookService, lineClient *MockLineClient, text string) string {
+	t.Helper()
+	if err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent(text)}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if lineClient.LastReplyRequest == nil {
+		t.Fatal("Expected a reply")
+	}
+	return lineClient.LastReplyRequest.Messages[0].Text
+}
+
+// testTodos returns two todos owned by the test user, the second one complete
+func testTodos() []domain.TodoResponse {
+	firstID := uuid.MustParse("7f1c2a4e-0000-4000-8000-000000000001")
+	secondID := uuid.MustParse("7f1c2a4e-0000-4000-8000-000000000002")
+	firstTitle, secondTitle := "Buy milk", "Call mom"
+	inProgress, complete := domain.TodoStatusInProgress, domain.TodoStatusComplete
+	return []domain.TodoResponse{
+		{ID: &firstID, Title: &firstTitle, Status: &inProgress},
+		{ID: &secondID, Title: &secondTitle, Status: &complete},
+	}
+}
+
+// TestTodoCommand_Add tests that /todo add creates an in-progress todo owned by the sender
+func TestTodoCommand_Add(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	todoService := &MockTodoService{}
+	service := newTodoCommandService(mockLineClient, todoService)
+
+	// Act
+	reply := sendTodoCommand(t, service, mockLineClient, "/todo add Buy milk today")
+
+	// Assert
+	request := todoService.LastCreateRequest
+	if request == nil {
+		t.Fatal("Expected CreateTodo to be called")
+	}
+	if *request.Title != "Buy milk today" || *request.Status != domain.TodoStatusInProgress || request.Date == nil {
+		t.Errorf("Unexpected create request: %+v", request)
+	}
+	if request.OwnerID == nil || *request.OwnerID != "test-user-id" {
+		t.Errorf("Expected todo owned by the sender, got %v", request.OwnerID)
+	}
+	if reply != "Added: Buy milk today" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
+
+// TestTodoCommand_List tests that /todo list shows the sender's todos, numbered and oldest first
+func TestTodoCommand_List(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	todoService := &MockTodoService{
+		GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+			return &domain.TodoListResponse{Todos: testTodos()}, nil
+		},
+	}
+	service := newTodoCommandService(mockLineClient, todoService)
+
+	// Act
+	reply := sendTodoCommand(t, service, mockLineClient, "/todo list")
+
+	// Assert
+	condition := todoService.LastGetCondition
+	if condition.OwnerID == nil || *condition.OwnerID != "test-user-id" {
+		t.Errorf("Expected todos filtered by owner, got %v", condition.OwnerID)
+	}
+	if condition.OrderBy == nil || *condition.OrderBy != "created_at" {
+		t.Errorf("Expected todos ordered by creation, got %v", condition.OrderBy)
+	}
+	if reply != "Your todos:\n1. [ ] Buy milk\n2. [x] Call mom" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
+
+// TestTodoCommand_DoneByNumber tests that /todo done <number> completes the numbered todo
+func TestTodoCommand_DoneByNumber(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	todoService := &MockTodoService{
+		GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+			return &domain.TodoListResponse{Todos: testTodos()}, nil
+		},
+	}
+	service := newTodoCommandService(mockLineClient, todoService)
+
+	// Act
+	reply := sendTodoCommand(t, service, mockLineClient, "/todo done 1")
+
+	// Assert
+	request := todoService.LastUpdateRequest
+	if request == nil {
+		t.Fatal("Expected UpdateTodo to be called")
+	}
+	if *request.ID != *testTodos()[0].ID || *request.Status != domain.TodoStatusComplete || *request.OwnerID != "test-user-id" {
+		t.Errorf("Unexpected update request: %+v", request)
+	}
+	if reply != "Done: Buy milk" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
+
+// TestTodoCommand_DeleteByID tests that /todo delete <id> only looks up the sender's own todo
+func TestTodoCommand_DeleteByID(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	todo := testTodos()[1]
+	todoService := &MockTodoService{
+		GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+			if condition.ID == nil || *condition.ID != *todo.ID || *condition.OwnerID != "test-user-id" {
+				return &domain.TodoListResponse{}, nil
+			}
+			return &domain.TodoListResponse{Todos: []domain.TodoResponse{todo}}, nil
+		},
+	}
+	service := newTodoCommandService(mockLineClient, todoService)
+
+	// Act
+	reply := sendTodoCommand(t, service, mockLineClient, "/todo delete "+todo.ID.String())
+
+	// Assert
+	request := todoService.LastDeleteRequest
+	if request == nil || *request.ID != *todo.ID || *request.OwnerID != "test-user-id" {
+		t.Fatalf("Unexpected delete request: %+v", request)
+	}
+	if reply != "Deleted: Call mom" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
+
+// TestTodoCommand_NotFound tests that todos outside the sender's list are not changed
+func TestTodoCommand_NotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"number past the list", "/todo done 3"},
+		{"someone else's todo", "/todo delete 7f1c2a4e-0000-4000-8000-000000000009"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockLineClient := &MockLineClient{}
+			todoService := &MockTodoService{
+				GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+					if condition.ID != nil {
+						return &domain.TodoListResponse{}, nil
+					}
+					return &domain.TodoListResponse{Todos: testTodos()}, nil
+				},
+			}
+			service := newTodoCommandService(mockLineClient, todoService)
+
+			// Act
+			reply := sendTodoCommand(t, service, mockLineClient, tt.text)
+
+			// Assert
+			if todoService.LastUpdateRequest != nil || todoService.LastDeleteRequest != nil {
+				t.Error("Expected no todo to be changed")
+			}
+			if !strings.HasPrefix(reply, "Todo not found") {
+				t.Errorf("Unexpected reply: %q", reply)
+			}
+		})
+	}
+}
+
+// TestTodoCommand_UsageAndErrors tests usage replies, unknown senders, and todo store failures
+func TestTodoCommand_UsageAndErrors(t *testing.T) {
+	failing := &MockTodoService{
+		CreateTodoFunc: func(request domain.TodoRequest) (*domain.TodoResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	tests := []struct {
+		name        string
+		todoService *MockTodoService
+		userID      string
+		text        string
+		expected    string
+	}{
+		{"no subcommand", &MockTodoService{}, "test-user-id", "/todo", todoUsageMessage},
+		{"unknown subcommand", &MockTodoService{}, "test-user-id", "/todo rename 1", todoUsageMessage},
+		{"add without title", &MockTodoService{}, "test-user-id", "/todo add", "Usage: /todo add <title>"},
+		{"done without number", &MockTodoService{}, "test-user-id", "/todo done first", "Usage: /todo done <number from /todo list>"},
+		{"unknown sender", &MockTodoService{}, "", "/todo list", todoOwnerUnknownMessage},
+		{"store failure", failing, "test-user-id", "/todo add Buy milk", todoErrorMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockLineClient := &MockLineClient{}
+			service := newTodoCommandService(mockLineClient, tt.todoService)
+			event := createTextMessageEvent(tt.text)
+			event.Source.UserID = tt.userID
+
+			// Act
+			err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{event}})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if reply := mockLineClient.LastReplyRequest.Messages[0].Text; reply != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, reply)
+			}
+		})
+	}
+}
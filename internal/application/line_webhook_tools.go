@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Maximum number of tool-calling rounds before the model is asked to answer without tools
+const maxToolRounds = 5
+
+// WithTools func - Offers the registry's tools to LM Studio and runs the calls the model makes
+// Replies are not streamed while tools are registered, since tool calls are resolved before
+// the model writes its answer.
+func WithTools(registry *ToolRegistry) LineWebhookServiceOption {
+	return func(s *LineWebhookService) {
+		s.tools = registry
+	}
+}
+
+// usesTools - Helper method to check whether any tools are offered to the model
+func (s *LineWebhookService) usesTools() bool {
+	return s.tools != nil && s.tools.Len() > 0
+}
+
+// completeChat - Helper method to get the model's answer, running any tools it calls
+// Each round the model's tool calls are executed for userID, the LINE user being answered, and
+// their results appended to the request, until the model answers with text. After maxToolRounds
+// the model is asked once more without tools so it has to answer. Tool messages are not kept
+// in conversation history.
+func (s *LineWebhookService) completeChat(ctx context.Context, userID string, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	if !s.usesTools() {
+		return s.lmStudioClient.ChatCompletion(ctx, request)
+	}
+
+	// Copy the messages so tool rounds never write into the caller's slice
+	request.Messages = append([]domain.ChatMessage(nil), request.Messages...)
+	request.Tools = s.tools.Definitions()
+
+	for round := 1; round <= maxToolRounds; round++ {
+		response, err := s.lmStudioClient.ChatCompletion(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.ToolCalls) == 0 {
+			return response, nil
+		}
+
+		logrus.Infof("Model requested %d tool call(s) in round %d", len(response.ToolCalls), round)
+
+		request.Messages = append(request.Messages, domain.ChatMessage{
+			Role:      domain.ChatMessageRoleAssistant,
+			Content:   response.Content,
+			ToolCalls: response.ToolCalls,
+		})
+		for _, call := range response.ToolCalls {
+			request.Messages = append(request.Messages, domain.ChatMessage{
+				Role:       domain.ChatMessageRoleTool,
+				Content:    s.tools.Call(ctx, userID, call),
+				ToolCallID: call.ID,
+			})
+		}
+	}
+
+	logrus.Warnf("Model still calling tools after %d rounds, asking for an answer without tools", maxToolRounds)
+	request.Tools = nil
+	return s.lmStudioClient.ChatCompletion(ctx, request)
+}
@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// newEchoTool creates a tool that returns its arguments prefixed with the tool name
+func newEchoTool(name string) Tool {
+	return Tool{
+		Name:        name,
+		Description: "Echo the arguments",
+		Parameters:  json.RawMessage(`{"type":"object"}`),
+		Handler: func(ctx context.Context, userID string, arguments json.RawMessage) (string, error) {
+			return name + ":" + string(arguments), nil
+		},
+	}
+}
+
+// newToolTestRegistry creates a registry with the given tools, failing the test on error
+func newToolTestRegistry(t *testing.T, tools ...Tool) *ToolRegistry {
+	t.Helper()
+	registry := NewToolRegistry()
+	if err := registry.Register(tools...); err != nil {
+		t.Fatalf("failed to register tools: %v", err)
+	}
+	return registry
+}
+
+// TestToolRegistry_RegisterRejectsDuplicatesAndMissingHandlers tests registry validation
+func TestToolRegistry_RegisterRejectsDuplicatesAndMissingHandlers(t *testing.T) {
+	registry := newToolTestRegistry(t, newEchoTool("a"))
+
+	if err := registry.Register(newEchoTool("a")); err == nil {
+		t.Error("Expected error for duplicate tool name")
+	}
+	if err := registry.Register(Tool{Name: "b"}); err == nil {
+		t.Error("Expected error for tool without handler")
+	}
+	if registry.Len() != 1 {
+		t.Errorf("Expected 1 tool, got %d", registry.Len())
+	}
+}
+
+// TestToolRegistry_CallReportsErrorsToModel tests that failures become {"error": ...} results
+func TestToolRegistry_CallReportsErrorsToModel(t *testing.T) {
+	failing := Tool{
+		Name: "fail",
+		Handler: func(ctx context.Context, userID string, arguments json.RawMessage) (string, error) {
+			return "", errors.New("database down")
+		},
+	}
+	registry := newToolTestRegistry(t, newEchoTool("echo"), failing)
+
+	tests := []struct {
+		name     string
+		call     domain.ChatToolCall
+		expected string
+	}{
+		{"success", domain.ChatToolCall{Name: "echo", Arguments: `{"x":1}`}, `echo:{"x":1}`},
+		{"empty arguments", domain.ChatToolCall{Name: "echo"}, `echo:{}`},
+		{"unknown tool", domain.ChatToolCall{Name: "missing"}, `{"error":"unknown tool: missing"}`},
+		{"invalid arguments", domain.ChatToolCall{Name: "echo", Arguments: `{`}, `{"error":"arguments are not valid JSON"}`},
+		{"handler error", domain.ChatToolCall{Name: "fail", Arguments: `{}`}, `{"error":"database down"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.Call(context.Background(), "test-user-id", tt.call); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestTools_ExecutesToolCallsUntilModelAnswers tests the tool loop: definitions are sent,
+// tool calls are run, and their results are fed back until the model replies with text
+func TestTools_ExecutesToolCallsUntilModelAnswers(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	var requests []domain.ChatCompletionRequest
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			requests = append(requests, request)
+			if len(requests) == 1 {
+				return &domain.ChatCompletionResponse{ToolCalls: []domain.ChatToolCall{
+					{ID: "call_1", Name: "echo", Arguments: `{"x":1}`},
+				}}, nil
+			}
+			return &domain.ChatCompletionResponse{Content: "Done!"}, nil
+		},
+	}
+	mockSessionStore := &MockSessionStore{}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, mockSessionStore, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithTools(newToolTestRegistry(t, newEchoTool("echo"))))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Do it")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 chat completions, got %d", len(requests))
+	}
+	if len(requests[0].Tools) != 1 || requests[0].Tools[0].Name != "echo" {
+		t.Errorf("Expected echo tool definition, got %+v", requests[0].Tools)
+	}
+	if len(requests[0].Messages) != 2 {
+		t.Errorf("Expected first request to contain system and user messages only, got %d", len(requests[0].Messages))
+	}
+
+	messages := requests[1].Messages
+	if len(messages) != 4 {
+		t.Fatalf("Expected 4 messages in second request, got %d", len(messages))
+	}
+	if messages[2].Role != domain.ChatMessageRoleAssistant || len(messages[2].ToolCalls) != 1 {
+		t.Errorf("Expected assistant tool call message, got %+v", messages[2])
+	}
+	if messages[3].Role != domain.ChatMessageRoleTool || messages[3].ToolCallID != "call_1" || messages[3].Content != `echo:{"x":1}` {
+		t.Errorf("Expected tool result message, got %+v", messages[3])
+	}
+
+	if reply := mockLineClient.LastReplyRequest; reply == nil || reply.Messages[0].Text != "Done!" {
+		t.Errorf("Expected final answer as reply, got %+v", reply)
+	}
+
+	// Only the user's message and the final answer are kept in history
+	history := mockSessionStore.LastUpdatedSession.GetHistory()
+	if len(history) != 2 || history[1].Content != "Done!" || len(history[1].ToolCalls) != 0 {
+		t.Errorf("Expected user and final assistant message in history, got %+v", history)
+	}
+}
+
+// TestTools_StopsAfterMaxRounds tests that a model that keeps calling tools is finally asked without tools
+func TestTools_StopsAfterMaxRounds(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	var requests []domain.ChatCompletionRequest
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			requests = append(requests, request)
+			if len(request.Tools) == 0 {
+				return &domain.ChatCompletionResponse{Content: "Giving up on tools"}, nil
+			}
+			return &domain.ChatCompletionResponse{ToolCalls: []domain.ChatToolCall{{ID: "call", Name: "echo"}}}, nil
+		},
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithTools(newToolTestRegistry(t, newEchoTool("echo"))))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Loop")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(requests) != maxToolRounds+1 {
+		t.Errorf("Expected %d chat completions, got %d", maxToolRounds+1, len(requests))
+	}
+	if reply := mockLineClient.LastReplyRequest; reply == nil || reply.Messages[0].Text != "Giving up on tools" {
+		t.Errorf("Expected answer without tools as reply, got %+v", reply)
+	}
+}
+
+// TestTools_ErrorUsesFriendlyMessage tests that LM Studio errors during the tool loop are not exposed
+func TestTools_ErrorUsesFriendlyMessage(t *testing.T) {
+	// Arrange
+	mockLineClient := &MockLineClient{}
+	calls := 0
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			calls++
+			if calls == 1 {
+				return &domain.ChatCompletionResponse{ToolCalls: []domain.ChatToolCall{{ID: "call", Name: "echo"}}}, nil
+			}
+			return nil, errors.New("connection reset by peer")
+		},
+	}
+	service := NewLineWebhookService(mockLineClient, mockLMStudioClient, &MockSessionStore{}, "You are a helpful assistant", defaultTestTimeout, defaultTestMaxTurns,
+		WithTools(newToolTestRegistry(t, newEchoTool("echo"))))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Do it")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	reply := mockLineClient.LastReplyRequest
+	if reply == nil || reply.Messages[0].Text != lmStudioErrorMessage || strings.Contains(reply.Messages[0].Text, "connection") {
+		t.Errorf("Expected friendly error message, got %+v", reply)
+	}
+}
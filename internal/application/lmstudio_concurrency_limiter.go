@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
+)
+
+// Compile-time check to ensure LMStudioConcurrencyLimiter implements LMStudioClient interface
+var _ output.LMStudioClient = (*LMStudioConcurrencyLimiter)(nil)
+
+// LMStudioConcurrencyLimiter struct - Decorator capping concurrent chat completions across all users
+// A single LM Studio instance serves one generation at a time well; extra requests wait for a
+// free slot for up to waitTimeout and then fail fast with ErrLMStudioBusy.
+type LMStudioConcurrencyLimiter struct {
+	client      output.LMStudioClient
+	slots       chan struct{}
+	waitTimeout time.Duration
+}
+
+// NewLMStudioConcurrencyLimiter func - Creates a concurrency limited LM Studio client
+// maxConcurrent: Maximum number of chat completions in flight at once
+// waitTimeout: How long a request waits for a free slot before failing with ErrLMStudioBusy
+func NewLMStudioConcurrencyLimiter(client output.LMStudioClient, maxConcurrent int, waitTimeout time.Duration) *LMStudioConcurrencyLimiter {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+
+	return &LMStudioConcurrencyLimiter{
+		client:      client,
+		slots:       make(chan struct{}, maxConcurrent),
+		waitTimeout: waitTimeout,
+	}
+}
+
+// ChatCompletion func - Sends a chat completion once a slot is free
+func (l *LMStudioConcurrencyLimiter) ChatCompletion(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+
+	return l.client.ChatCompletion(ctx, request)
+}
+
+// ChatCompletionStream func - Starts a streaming chat completion once a slot is free
+// The slot is held until the stream's channel is closed.
+func (l *LMStudioConcurrencyLimiter) ChatCompletionStream(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	chunks, err := l.client.ChatCompletionStream(ctx, request)
+	if err != nil || chunks == nil {
+		l.release()
+		return chunks, err
+	}
+
+	out := make(chan domain.ChatCompletionChunk)
+	go func() {
+		defer close(out)
+		defer l.release()
+
+		for chunk := range chunks {
+			select {
+			case out <- chunk:
+			case <-ctx.Done():
+				// Consumer is gone; let the producer finish so it is never left blocked
+				for range chunks {
+				}
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// ListModels func - Lists models without taking a slot
+func (l *LMStudioConcurrencyLimiter) ListModels(ctx context.Context) ([]domain.ModelInfo, error) {
+	return l.client.ListModels(ctx)
+}
+
+// acquire - Waits for a free slot, the wait timeout, or the context, whichever comes first
+func (l *LMStudioConcurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(l.waitTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%w: no free slot after %v", domain.ErrLMStudioBusy, l.waitTimeout)
+	case <-ctx.Done():
+		return fmt.Errorf("context cancelled: %w", ctx.Err())
+	}
+}
+
+// release - Frees a slot taken by acquire
+func (l *LMStudioConcurrencyLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-template/internal/domain"
+)
+
+// slowLMStudioClient implements output.LMStudioClient, recording the peak number of concurrent calls
+type slowLMStudioClient struct {
+	MockLMStudioClient
+
+	inFlight, peak atomic.Int32
+}
+
+func (c *slowLMStudioClient) ChatCompletion(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	n := c.inFlight.Add(1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	c.inFlight.Add(-1)
+	return &domain.ChatCompletionResponse{Content: "ok"}, nil
+}
+
+// TestConcurrencyLimiter_CapsInFlightRequests tests that no more than maxConcurrent
+// chat completions run at the same time
+func TestConcurrencyLimiter_CapsInFlightRequests(t *testing.T) {
+	// Arrange
+	client := &slowLMStudioClient{}
+	limiter := NewLMStudioConcurrencyLimiter(client, 2, time.Second)
+
+	// Act
+	done := make(chan error)
+	for i := 0; i < 10; i++ {
+		go func() {
+			_, err := limiter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{})
+			done <- err
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	}
+
+	// Assert
+	if client.peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", client.peak.Load())
+	}
+}
+
+// TestConcurrencyLimiter_ReturnsBusyAfterWaitTimeout tests that a request fails fast with
+// ErrLMStudioBusy when no slot frees up in time
+func TestConcurrencyLimiter_ReturnsBusyAfterWaitTimeout(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	started := make(chan struct{})
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			close(started)
+			<-release
+			return &domain.ChatCompletionResponse{Content: "ok"}, nil
+		},
+	}
+	limiter := NewLMStudioConcurrencyLimiter(mockLMStudioClient, 1, 20*time.Millisecond)
+	go func() { _, _ = limiter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{}) }()
+	<-started
+	defer close(release)
+
+	// Act
+	_, err := limiter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{})
+
+	// Assert
+	if !errors.Is(err, domain.ErrLMStudioBusy) {
+		t.Errorf("Expected ErrLMStudioBusy, got: %v", err)
+	}
+}
+
+// TestConcurrencyLimiter_StreamHoldsSlotUntilClosed tests that a streaming request keeps its
+// slot until the stream channel is closed
+func TestConcurrencyLimiter_StreamHoldsSlotUntilClosed(t *testing.T) {
+	// Arrange
+	source := make(chan domain.ChatCompletionChunk)
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionStreamFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (<-chan domain.ChatCompletionChunk, error) {
+			return source, nil
+		},
+	}
+	limiter := NewLMStudioConcurrencyLimiter(mockLMStudioClient, 1, 10*time.Millisecond)
+
+	chunks, err := limiter.ChatCompletionStream(context.Background(), domain.ChatCompletionRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error starting stream, got: %v", err)
+	}
+
+	// Act & Assert - slot is taken while the stream is open
+	if _, err := limiter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{}); !errors.Is(err, domain.ErrLMStudioBusy) {
+		t.Errorf("Expected ErrLMStudioBusy while stream is open, got: %v", err)
+	}
+
+	go func() {
+		source <- domain.ChatCompletionChunk{Content: "Hello", Done: true}
+		close(source)
+	}()
+	for chunk := range chunks {
+		if chunk.Content != "Hello" {
+			t.Errorf("Expected forwarded chunk 'Hello', got %q", chunk.Content)
+		}
+	}
+
+	// Slot is free once the stream is closed
+	if _, err := limiter.ChatCompletion(context.Background(), domain.ChatCompletionRequest{}); err != nil {
+		t.Errorf("Expected slot to be released after stream closed, got: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/input"
+)
+
+// Tool names of the todo use cases offered to the model
+const (
+	createTodoToolName = "create_todo"
+	listTodosToolName  = "list_todos"
+)
+
+// Maximum todo title length, matching the todos table and the HTTP API's validation
+const maxTodoTitleLength = 100
+
+// Default and maximum number of todos returned by list_todos
+const (
+	defaultTodoToolLimit = 10
+	maxTodoToolLimit     = 20
+)
+
+// createTodoToolParameters - JSON Schema of the create_todo arguments
+const createTodoToolParameters = `{
+	"type": "object",
+	"properties": {
+		"title": {"type": "string", "description": "Short title of the todo, at most 100 characters"},
+		"description": {"type": "string", "description": "Optional details"},
+		"date": {"type": "string", "description": "Due date as YYYY-MM-DD or YYYY-MM-DDTHH:MM:SSZ, defaults to now"},
+		"status": {"type": "string", "enum": ["IN_PROGRESS", "COMPLETE"], "description": "Defaults to IN_PROGRESS"}
+	},
+	"required": ["title"]
+}`
+
+// listTodosToolParameters - JSON Schema of the list_todos arguments
+const listTodosToolParameters = `{
+	"type": "object",
+	"properties": {
+		"status": {"type": "string", "enum": ["IN_PROGRESS", "COMPLETE"], "description": "Only list todos with this status"},
+		"limit": {"type": "integer", "minimum": 1, "maximum": 20, "description": "Maximum number of todos, defaults to 10"}
+	}
+}`
+
+// errTodoOwnerUnknown is returned when LINE did not share the user ID todos are scoped to
+var errTodoOwnerUnknown = errors.New("the user could not be identified, todos are unavailable")
+
+// todoToolResult - Todo as returned to the model
+type todoToolResult struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Status      string `json:"status"`
+}
+
+// NewTodoTools func - Creates the create_todo and list_todos tools backed by the todo use cases
+// Todos are scoped to the LINE user the model is answering, like the /todo command.
+func NewTodoTools(todoService input.TodoService) []Tool {
+	return []Tool{
+		{
+			Name:        createTodoToolName,
+			Description: "Create a todo item for the user.",
+			Parameters:  json.RawMessage(createTodoToolParameters),
+			Handler: func(ctx context.Context, userID string, arguments json.RawMessage) (string, error) {
+				return createTodoTool(todoService, userID, arguments)
+			},
+		},
+		{
+			Name:        listTodosToolName,
+			Description: "List existing todo items, optionally filtered by status.",
+			Parameters:  json.RawMessage(listTodosToolParameters),
+			Handler: func(ctx context.Context, userID string, arguments json.RawMessage) (string, error) {
+				return listTodosTool(todoService, userID, arguments)
+			},
+		},
+	}
+}
+
+// createTodoTool - Runs the create_todo tool via TodoService.CreateTodo
+func createTodoTool(todoService input.TodoService, userID string, arguments json.RawMessage) (string, error) {
+	if userID == "" {
+		return "", errTodoOwnerUnknown
+	}
+
+	var args struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Date        string `json:"date"`
+		Status      string `json:"status"`
+	}
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	title := strings.TrimSpace(args.Title)
+	if title == "" {
+		return "", fmt.Errorf("title is required")
+	}
+	if len(title) > maxTodoTitleLength {
+		return "", fmt.Errorf("title must be at most %d characters", maxTodoTitleLength)
+	}
+
+	date, err := parseTodoToolDate(args.Date)
+	if err != nil {
+		return "", err
+	}
+
+	status, err := parseTodoToolStatus(args.Status, domain.TodoStatusInProgress)
+	if err != nil {
+		return "", err
+	}
+
+	request := domain.TodoRequest{
+		Title:   &title,
+		Date:    &date,
+		Status:  &status,
+		OwnerID: &userID,
+	}
+	if description := strings.TrimSpace(args.Description); description != "" {
+		request.Description = &description
+	}
+
+	created, err := todoService.CreateTodo(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to create todo: %w", err)
+	}
+
+	return marshalToolResult(toTodoToolResult(*created))
+}
+
+// listTodosTool - Runs the list_todos tool via TodoService.GetTodo
+func listTodosTool(todoService input.TodoService, userID string, arguments json.RawMessage) (string, error) {
+	if userID == "" {
+		return "", errTodoOwnerUnknown
+	}
+
+	var args struct {
+		Status string `json:"status"`
+		Limit  int    `json:"limit"`
+	}
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	limit := defaultTodoToolLimit
+	if args.Limit > 0 {
+		limit = args.Limit
+	}
+	if limit > maxTodoToolLimit {
+		limit = maxTodoToolLimit
+	}
+
+	condition := domain.QueryTodoRequest{Limit: &limit, OwnerID: &userID}
+	if args.Status != "" {
+		status, err := parseTodoToolStatus(args.Status, "")
+		if err != nil {
+			return "", err
+		}
+		statusFilter := string(status)
+		condition.Status = &statusFilter
+	}
+
+	list, err := todoService.GetTodo(condition)
+	if err != nil {
+		return "", fmt.Errorf("failed to list todos: %w", err)
+	}
+
+	results := make([]todoToolResult, 0, len(list.Todos))
+	for _, todo := range list.Todos {
+		results = append(results, toTodoToolResult(todo))
+	}
+
+	return marshalToolResult(map[string]interface{}{"todos": results})
+}
+
+// parseTodoToolDate - Helper to convert the model's date to the todo date layout
+// Accepts a date or a UTC timestamp; an empty date means now.
+func parseTodoToolDate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Now().UTC().Format(domain.DatetimeLayout), nil
+	}
+
+	if date, err := time.Parse(domain.DatetimeLayout, value); err == nil {
+		return date.Format(domain.DatetimeLayout), nil
+	}
+	if date, err := time.Parse(domain.OnlyDate, value); err == nil {
+		return date.Format(domain.DatetimeLayout), nil
+	}
+
+	return "", fmt.Errorf("date %q must be YYYY-MM-DD or YYYY-MM-DDTHH:MM:SSZ", value)
+}
+
+// parseTodoToolStatus - Helper to validate the model's todo status, returning fallback when empty
+func parseTodoToolStatus(value string, fallback domain.TodoStatus) (domain.TodoStatus, error) {
+	switch status := domain.TodoStatus(strings.ToUpper(strings.TrimSpace(value))); status {
+	case "":
+		return fallback, nil
+	case domain.TodoStatusInProgress, domain.TodoStatusComplete:
+		return status, nil
+	default:
+		return "", fmt.Errorf("status %q must be IN_PROGRESS or COMPLETE", value)
+	}
+}
+
+// toTodoToolResult - Helper to convert a todo response to the result returned to the model
+func toTodoToolResult(todo domain.TodoResponse) todoToolResult {
+	var result todoToolResult
+	if todo.ID != nil {
+		result.ID = todo.ID.String()
+	}
+	if todo.Title != nil {
+		result.Title = *todo.Title
+	}
+	if todo.Description != nil {
+		result.Description = *todo.Description
+	}
+	if todo.Date != nil {
+		result.Date = *todo.Date
+	}
+	if todo.Status != nil {
+		result.Status = string(*todo.Status)
+	}
+	return result
+}
+
+// marshalToolResult - Helper to encode a tool result as JSON
+func marshalToolResult(result interface{}) (string, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,211 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// MockTodoService implements input.TodoService for testing
+type MockTodoService struct {
+	CreateTodoFunc func(request domain.TodoRequest) (*domain.TodoResponse, error)
+	UpdateTodoFunc func(request domain.TodoRequest) (*domain.TodoResponse, error)
+	DeleteTodoFunc func(request domain.TodoRequest) (*domain.TodoResponse, error)
+	GetTodoFunc    func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error)
+
+	// Captured values for assertions
+	LastCreateRequest *domain.TodoRequest
+	LastUpdateRequest *domain.TodoRequest
+	LastDeleteRequest *domain.TodoRequest
+	LastGetCondition  *domain.QueryTodoRequest
+}
+
+func (m *MockTodoService) CreateTodo(request domain.TodoRequest) (*domain.TodoResponse, error) {
+	m.LastCreateRequest = &request
+	if m.CreateTodoFunc != nil {
+		return m.CreateTodoFunc(request)
+	}
+	id := uuid.MustParse("7f1c2a4e-0000-4000-8000-000000000001")
+	return &domain.TodoResponse{ID: &id, Title: request.Title, Description: request.Description, Date: request.Date, Status: request.Status}, nil
+}
+
+func (m *MockTodoService) UpdateTodo(request domain.TodoRequest) (*domain.TodoResponse, error) {
+	m.LastUpdateRequest = &request
+	if m.UpdateTodoFunc != nil {
+		return m.UpdateTodoFunc(request)
+	}
+	return &domain.TodoResponse{ID: request.ID, Status: request.Status}, nil
+}
+
+func (m *MockTodoService) DeleteTodo(request domain.TodoRequest) (*domain.TodoResponse, error) {
+	m.LastDeleteRequest = &request
+	if m.DeleteTodoFunc != nil {
+		return m.DeleteTodoFunc(request)
+	}
+	return &domain.TodoResponse{ID: request.ID}, nil
+}
+
+func (m *MockTodoService) GetTodo(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+	m.LastGetCondition = &condition
+	if m.GetTodoFunc != nil {
+		return m.GetTodoFunc(condition)
+	}
+	return &domain.TodoListResponse{}, nil
+}
+
+// callTodoTool runs the named todo tool through a registry, as the tool loop does
+func callTodoTool(t *testing.T, todoService *MockTodoService, name, arguments string) string {
+	t.Helper()
+	registry := newToolTestRegistry(t, NewTodoTools(todoService)...)
+	return registry.Call(context.Background(), "test-user-id", domain.ChatToolCall{ID: "call", Name: name, Arguments: arguments})
+}
+
+// TestTodoTools_Definitions tests that both todo tools are offered with valid JSON Schemas
+func TestTodoTools_Definitions(t *testing.T) {
+	definitions := newToolTestRegistry(t, NewTodoTools(&MockTodoService{})...).Definitions()
+
+	if len(definitions) != 2 || definitions[0].Name != createTodoToolName || definitions[1].Name != listTodosToolName {
+		t.Fatalf("Expected create_todo and list_todos, got %+v", definitions)
+	}
+	for _, definition := range definitions {
+		if !json.Valid(definition.Parameters) {
+			t.Errorf("Expected valid JSON Schema for %s, got %s", definition.Name, definition.Parameters)
+		}
+	}
+}
+
+// TestTodoTools_CreateTodo tests that create_todo fills in defaults and calls CreateTodo
+func TestTodoTools_CreateTodo(t *testing.T) {
+	// Arrange
+	todoService := &MockTodoService{}
+
+	// Act
+	result := callTodoTool(t, todoService, createTodoToolName, `{"title":" Buy milk ","date":"2026-10-17"}`)
+
+	// Assert
+	request := todoService.LastCreateRequest
+	if request == nil {
+		t.Fatalf("Expected CreateTodo to be called, result: %s", result)
+	}
+	if *request.Title != "Buy milk" || request.Description != nil {
+		t.Errorf("Expected trimmed title and no description, got %+v", request)
+	}
+	if *request.Date != "2026-10-17T00:00:00Z" {
+		t.Errorf("Expected date in todo layout, got %s", *request.Date)
+	}
+	if *request.Status != domain.TodoStatusInProgress {
+		t.Errorf("Expected default status IN_PROGRESS, got %s", *request.Status)
+	}
+	if request.OwnerID == nil || *request.OwnerID != "test-user-id" {
+		t.Errorf("Expected todo owned by the user, got %v", request.OwnerID)
+	}
+
+	var created todoToolResult
+	if err := json.Unmarshal([]byte(result), &created); err != nil {
+		t.Fatalf("Expected JSON result, got %s", result)
+	}
+	if created.ID == "" || created.Title != "Buy milk" || created.Status != "IN_PROGRESS" {
+		t.Errorf("Expected created todo in result, got %+v", created)
+	}
+}
+
+// TestTodoTools_CreateTodoRejectsInvalidArguments tests that invalid arguments never reach CreateTodo
+func TestTodoTools_CreateTodoRejectsInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments string
+	}{
+		{"missing title", `{"date":"2026-10-17"}`},
+		{"title too long", `{"title":"` + strings.Repeat("a", maxTodoTitleLength+1) + `"}`},
+		{"invalid date", `{"title":"Buy milk","date":"tomorrow"}`},
+		{"invalid status", `{"title":"Buy milk","status":"DONE"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoService := &MockTodoService{}
+
+			result := callTodoTool(t, todoService, createTodoToolName, tt.arguments)
+
+			if todoService.LastCreateRequest != nil {
+				t.Error("Expected CreateTodo not to be called")
+			}
+			if !strings.HasPrefix(result, `{"error":`) {
+				t.Errorf("Expected error result, got %s", result)
+			}
+		})
+	}
+}
+
+// TestTodoTools_ListTodos tests that list_todos passes the filter and limit to GetTodo
+func TestTodoTools_ListTodos(t *testing.T) {
+	// Arrange
+	title := "Buy milk"
+	status := domain.TodoStatusComplete
+	todoService := &MockTodoService{
+		GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+			return &domain.TodoListResponse{Todos: []domain.TodoResponse{{Title: &title, Status: &status}}}, nil
+		},
+	}
+
+	// Act
+	result := callTodoTool(t, todoService, listTodosToolName, `{"status":"complete","limit":50}`)
+
+	// Assert
+	condition := todoService.LastGetCondition
+	if condition == nil {
+		t.Fatalf("Expected GetTodo to be called, result: %s", result)
+	}
+	if condition.OwnerID == nil || *condition.OwnerID != "test-user-id" {
+		t.Errorf("Expected todos filtered by owner, got %v", condition.OwnerID)
+	}
+	if condition.Status == nil || *condition.Status != "COMPLETE" {
+		t.Errorf("Expected COMPLETE status filter, got %v", condition.Status)
+	}
+	if *condition.Limit != maxTodoToolLimit {
+		t.Errorf("Expected limit capped at %d, got %d", maxTodoToolLimit, *condition.Limit)
+	}
+	if result != `{"todos":[{"id":"","title":"Buy milk","status":"COMPLETE"}]}` {
+		t.Errorf("Unexpected result: %s", result)
+	}
+}
+
+// TestTodoTools_ListTodosError tests that GetTodo failures are reported to the model
+func TestTodoTools_ListTodosError(t *testing.T) {
+	todoService := &MockTodoService{
+		GetTodoFunc: func(condition domain.QueryTodoRequest) (*domain.TodoListResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	result := callTodoTool(t, todoService, listTodosToolName, `{}`)
+
+	if *todoService.LastGetCondition.Limit != defaultTodoToolLimit {
+		t.Errorf("Expected default limit %d, got %d", defaultTodoToolLimit, *todoService.LastGetCondition.Limit)
+	}
+	if result != `{"error":"failed to list todos: connection refused"}` {
+		t.Errorf("Unexpected result: %s", result)
+	}
+}
+
+// TestTodoTools_RequireUser tests that todo tools refuse to run without a LINE user ID
+func TestTodoTools_RequireUser(t *testing.T) {
+	todoService := &MockTodoService{}
+	registry := newToolTestRegistry(t, NewTodoTools(todoService)...)
+
+	for _, name := range []string{createTodoToolName, listTodosToolName} {
+		result := registry.Call(context.Background(), "", domain.ChatToolCall{Name: name, Arguments: `{"title":"Buy milk"}`})
+		if !strings.HasPrefix(result, `{"error":`) {
+			t.Errorf("Expected error result for %s, got %s", name, result)
+		}
+	}
+	if todoService.LastCreateRequest != nil || todoService.LastGetCondition != nil {
+		t.Error("Expected TodoService not to be called")
+	}
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// messageTokenOverhead approximates the tokens each chat message adds for its role and delimiters
+const messageTokenOverhead = 4
+
+// fitToTokenBudget - Helper method to trim history so the prompt fits within the token budget
+// The given system prompt and new user message are always kept. A rolling summary at the start
+// of the history is kept as well while it fits, so only the turns after it are trimmed. Turns are
+// dropped oldest first and never start with an assistant reply. If the system prompt and new
+// message alone exceed the budget, the new message is truncated to the tokens that remain.
+func (s *LineWebhookService) fitToTokenBudget(systemPrompt string, history []domain.ChatMessage, userMessage string) ([]domain.ChatMessage, string) {
+	if s.tokenEstimator == nil {
+		return history, userMessage
+	}
+	limit := s.tokenBudget.PromptTokens(s.currentModel())
+	if limit <= 0 {
+		return history, userMessage
+	}
+
+	systemPromptTokens := s.messageTokens(systemPrompt)
+	used := systemPromptTokens + s.messageTokens(userMessage)
+	if used > limit {
+		available := limit - systemPromptTokens - messageTokenOverhead
+		if available < 1 {
+			logrus.Warnf("System prompt alone exceeds the prompt budget of %d tokens", limit)
+			return nil, userMessage
+		}
+		truncated := s.truncateToTokens(userMessage, available)
+		logrus.Warnf("User message truncated from %d to %d characters to fit the prompt budget of %d tokens",
+			len(userMessage), len(truncated), limit)
+		return nil, truncated
+	}
+
+	// Pin the rolling summary, charging its cost up front like the system prompt
+	var summary []domain.ChatMessage
+	turns := history
+	if len(history) > 0 && history[0].Role == domain.ChatMessageRoleSystem {
+		turns = history[1:]
+		if cost := s.messageTokens(history[0].Content); used+cost <= limit {
+			summary = history[:1]
+			used += cost
+		} else {
+			logrus.Warnf("Dropped the conversation summary to fit the prompt budget of %d tokens", limit)
+		}
+	}
+
+	// Keep the newest turns that fit
+	start := len(turns)
+	for start > 0 {
+		cost := s.messageTokens(turns[start-1].Content)
+		if used+cost > limit {
+			break
+		}
+		used += cost
+		start--
+	}
+
+	// Don't start the history halfway through a turn
+	for start < len(turns) && turns[start].Role == domain.ChatMessageRoleAssistant {
+		start++
+	}
+
+	if start > 0 {
+		logrus.Infof("Dropped %d history messages to fit the prompt budget of %d tokens", start, limit)
+	}
+
+	kept := make([]domain.ChatMessage, 0, len(summary)+len(turns)-start)
+	kept = append(kept, summary...)
+	return append(kept, turns[start:]...), userMessage
+}
+
+// messageTokens - Helper method to estimate the tokens a chat message with the given content uses
+func (s *LineWebhookService) messageTokens(content string) int {
+	return s.tokenEstimator.EstimateTokens(content) + messageTokenOverhead
+}
+
+// truncateToTokens - Helper method to return the longest prefix of text estimated within maxTokens
+func (s *LineWebhookService) truncateToTokens(text string, maxTokens int) string {
+	runes := []rune(text)
+
+	// Binary search for the longest rune prefix that fits
+	low, high := 0, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if s.tokenEstimator.EstimateTokens(string(runes[:mid])) <= maxTokens {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return string(runes[:low])
+}
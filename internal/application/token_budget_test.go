@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"golang-template/internal/domain"
+)
+
+// charTokenEstimator implements output.TokenEstimator counting one token per byte
+type charTokenEstimator struct{}
+
+func (charTokenEstimator) EstimateTokens(text string) int {
+	return len(text)
+}
+
+// historyOf creates alternating user/assistant messages with the given contents
+func historyOf(contents ...string) []domain.ChatMessage {
+	history := make([]domain.ChatMessage, len(contents))
+	for i, content := range contents {
+		role := domain.ChatMessageRoleUser
+		if i%2 == 1 {
+			role = domain.ChatMessageRoleAssistant
+		}
+		history[i] = domain.ChatMessage{Role: role, Content: content}
+	}
+	return history
+}
+
+// promptTokens returns the prompt size of a request as measured by charTokenEstimator
+func promptTokens(request domain.ChatCompletionRequest) int {
+	total := 0
+	for _, msg := range request.Messages {
+		total += len(msg.Content) + messageTokenOverhead
+	}
+	return total
+}
+
+// TestBuildChatRequest_TrimsOldestHistoryToTokenBudget tests that the oldest turns are dropped
+// until system prompt, history, and new message fit the budget
+func TestBuildChatRequest_TrimsOldestHistoryToTokenBudget(t *testing.T) {
+	// Arrange
+	// system (6+4) + new message (5+4) + last turn (2*(10+4)) = 47 tokens
+	budget := domain.TokenBudget{ContextTokens: 60, ReplyTokens: 10}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, budget))
+
+	history := historyOf("old-q-0001", "old-a-0001", "new-q-0002", "new-a-0002")
+
+	// Act
+	request := service.buildChatRequest("", "hello", history)
+
+	// Assert
+	if len(request.Messages) != 4 {
+		t.Fatalf("Expected system + last turn + new message (4 messages), got %d", len(request.Messages))
+	}
+	if request.Messages[1].Content != "new-q-0002" || request.Messages[2].Content != "new-a-0002" {
+		t.Errorf("Expected only the newest turn to be kept, got %+v", request.Messages[1:3])
+	}
+	if got := promptTokens(request); got > budget.PromptTokens("") {
+		t.Errorf("Expected prompt to fit %d tokens, got %d", budget.PromptTokens(""), got)
+	}
+}
+
+// TestBuildChatRequest_HistoryNeverStartsWithAssistant tests that a turn is not split
+// leaving an orphaned assistant reply at the start of the history
+func TestBuildChatRequest_HistoryNeverStartsWithAssistant(t *testing.T) {
+	// Arrange
+	// Room for the new message, system prompt and three history messages
+	budget := domain.TokenBudget{ContextTokens: 61}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, budget))
+
+	history := historyOf("q-1-000000", "a-1-000000", "q-2-000000", "a-2-000000")
+
+	// Act
+	request := service.buildChatRequest("", "hello", history)
+
+	// Assert
+	if request.Messages[1].Role != domain.ChatMessageRoleUser {
+		t.Errorf("Expected history to start with a user message, got %s", request.Messages[1].Role)
+	}
+	if len(request.Messages) != 4 {
+		t.Errorf("Expected system + last turn + new message (4 messages), got %d", len(request.Messages))
+	}
+}
+
+// TestBuildChatRequest_KeepsSummaryWhenTrimmingHistory tests that a leading rolling summary
+// is kept and only the turns after it are dropped when the history exceeds the budget
+func TestBuildChatRequest_KeepsSummaryWhenTrimmingHistory(t *testing.T) {
+	// Arrange
+	// system (6+4) + summary (14+4) + new message (5+4) + last turn (2*(10+4)) = 65 tokens
+	budget := domain.TokenBudget{ContextTokens: 70}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, budget))
+
+	summary := domain.ChatMessage{Role: domain.ChatMessageRoleSystem, Content: "summary-000001"}
+	history := append([]domain.ChatMessage{summary}, historyOf("old-q-0001", "old-a-0001", "new-q-0002", "new-a-0002")...)
+
+	// Act
+	request := service.buildChatRequest("", "hello", history)
+
+	// Assert
+	if len(request.Messages) != 5 {
+		t.Fatalf("Expected system + summary + last turn + new message (5 messages), got %d", len(request.Messages))
+	}
+	if request.Messages[1].Role != domain.ChatMessageRoleSystem || request.Messages[1].Content != summary.Content {
+		t.Errorf("Expected the summary to be kept after the system prompt, got %+v", request.Messages[1])
+	}
+	if request.Messages[2].Content != "new-q-0002" || request.Messages[3].Content != "new-a-0002" {
+		t.Errorf("Expected only the newest turn to be kept, got %+v", request.Messages[2:4])
+	}
+	if got := promptTokens(request); got > budget.PromptTokens("") {
+		t.Errorf("Expected prompt to fit %d tokens, got %d", budget.PromptTokens(""), got)
+	}
+	if history[1].Content != "old-q-0001" {
+		t.Errorf("Expected the session history to be left untouched, got %+v", history[1])
+	}
+}
+
+// TestBuildChatRequest_TruncatesNewMessageWhenOverBudget tests that an oversized new message
+// is truncated so the request still fits
+func TestBuildChatRequest_TruncatesNewMessageWhenOverBudget(t *testing.T) {
+	// Arrange
+	budget := domain.TokenBudget{ContextTokens: 50}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, budget))
+
+	// Act
+	request := service.buildChatRequest("", strings.Repeat("x", 200), historyOf("q", "a"))
+
+	// Assert
+	if len(request.Messages) != 2 {
+		t.Fatalf("Expected system + new message only, got %d messages", len(request.Messages))
+	}
+	if got := promptTokens(request); got != 50 {
+		t.Errorf("Expected prompt to use exactly the 50 token budget, got %d", got)
+	}
+}
+
+// TestBuildChatRequest_UsesPerModelBudget tests that the configured model's context size is used
+func TestBuildChatRequest_UsesPerModelBudget(t *testing.T) {
+	// Arrange
+	budget := domain.TokenBudget{
+		ContextTokens:      30,
+		ModelContextTokens: map[string]int{"large-model": 1000},
+	}
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, budget), WithModel("large-model"))
+
+	history := historyOf("q-1", "a-1", "q-2", "a-2")
+
+	// Act
+	request := service.buildChatRequest("", "hello", history)
+
+	// Assert
+	if len(request.Messages) != 6 {
+		t.Errorf("Expected full history to fit the large model budget, got %d messages", len(request.Messages))
+	}
+	if request.Model == nil || *request.Model != "large-model" {
+		t.Errorf("Expected request model 'large-model', got %v", request.Model)
+	}
+}
+
+// TestBuildChatRequest_NoBudgetKeepsFullHistory tests that history is untouched without a budget
+func TestBuildChatRequest_NoBudgetKeepsFullHistory(t *testing.T) {
+	// Arrange
+	service := NewLineWebhookService(&MockLineClient{}, &MockLMStudioClient{}, &MockSessionStore{}, "system", defaultTestTimeout, defaultTestMaxTurns)
+	history := historyOf(strings.Repeat("q", 10000), strings.Repeat("a", 10000))
+
+	// Act
+	request := service.buildChatRequest("", "hello", history)
+
+	// Assert
+	if len(request.Messages) != 4 {
+		t.Errorf("Expected all messages to be kept, got %d", len(request.Messages))
+	}
+	if request.Model != nil {
+		t.Errorf("Expected model to be left to the client, got %v", *request.Model)
+	}
+}
+
+// TestHandleMessageEvent_SendsTrimmedHistory tests that the request sent to LM Studio is trimmed
+func TestHandleMessageEvent_SendsTrimmedHistory(t *testing.T) {
+	// Arrange
+	var captured domain.ChatCompletionRequest
+	mockLMStudioClient := &MockLMStudioClient{
+		ChatCompletionFunc: func(ctx context.Context, request domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+			captured = request
+			return &domain.ChatCompletionResponse{Content: "ok"}, nil
+		},
+	}
+	session := domain.NewConversationSession("test-user-id", defaultTestTimeout, defaultTestMaxTurns)
+	session.Messages = historyOf(strings.Repeat("q", 100), strings.Repeat("a", 100), "short q", "short a")
+	mockSessionStore := &MockSessionStore{
+		GetSessionFunc: func(userID string) (*domain.ConversationSession, error) {
+			return session, nil
+		},
+	}
+	service := NewLineWebhookService(&MockLineClient{}, mockLMStudioClient, mockSessionStore, "system", defaultTestTimeout, defaultTestMaxTurns,
+		WithTokenBudget(charTokenEstimator{}, domain.TokenBudget{ContextTokens: 100}))
+
+	// Act
+	err := service.HandleWebhook(domain.LineWebhookRequest{Events: []domain.LineWebhookEvent{createTextMessageEvent("Hi there!")}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(captured.Messages) != 4 {
+		t.Fatalf("Expected system + short turn + new message, got %d messages", len(captured.Messages))
+	}
+	if captured.Messages[1].Content != "short q" {
+		t.Errorf("Expected oldest long turn to be dropped, got %q", captured.Messages[1].Content)
+	}
+}
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"golang-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ToolHandler func - Runs a tool with the JSON arguments generated by the model
+// userID is the LINE user the model is answering, empty if LINE did not share it.
+// The returned string is sent back to the model as the tool's result.
+type ToolHandler func(ctx context.Context, userID string, arguments json.RawMessage) (string, error)
+
+// Tool struct - Go-side function the model may call
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage // JSON Schema of the arguments object
+	Handler     ToolHandler
+}
+
+// ToolRegistry struct - Tools offered to LM Studio, in registration order
+type ToolRegistry struct {
+	tools map[string]Tool
+	order []string
+}
+
+// NewToolRegistry func - Creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]Tool),
+	}
+}
+
+// Register func - Adds tools to the registry
+// Returns an error if a tool has no name or handler, or its name is already registered.
+func (r *ToolRegistry) Register(tools ...Tool) error {
+	for _, tool := range tools {
+		if tool.Name == "" || tool.Handler == nil {
+			return fmt.Errorf("tool %q must have a name and a handler", tool.Name)
+		}
+		if _, exists := r.tools[tool.Name]; exists {
+			return fmt.Errorf("tool %q is already registered", tool.Name)
+		}
+		r.tools[tool.Name] = tool
+		r.order = append(r.order, tool.Name)
+	}
+	return nil
+}
+
+// Len func - Returns the number of registered tools
+func (r *ToolRegistry) Len() int {
+	return len(r.order)
+}
+
+// Definitions func - Returns the tool definitions sent to LM Studio
+func (r *ToolRegistry) Definitions() []domain.ChatToolDefinition {
+	definitions := make([]domain.ChatToolDefinition, 0, len(r.order))
+	for _, name := range r.order {
+		tool := r.tools[name]
+		definitions = append(definitions, domain.ChatToolDefinition{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  tool.Parameters,
+		})
+	}
+	return definitions
+}
+
+// Call func - Runs the tool named by the call and returns its result for the model
+// Unknown tools, invalid arguments, and handler errors are reported to the model as an
+// {"error": "..."} result so it can recover, rather than failing the whole reply.
+func (r *ToolRegistry) Call(ctx context.Context, userID string, call domain.ChatToolCall) string {
+	tool, ok := r.tools[call.Name]
+	if !ok {
+		logrus.Warnf("Model called unknown tool: %s", call.Name)
+		return toolErrorResult(fmt.Errorf("unknown tool: %s", call.Name))
+	}
+
+	arguments := json.RawMessage(call.Arguments)
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	if !json.Valid(arguments) {
+		logrus.Warnf("Model called tool %s with invalid arguments: %s", call.Name, call.Arguments)
+		return toolErrorResult(fmt.Errorf("arguments are not valid JSON"))
+	}
+
+	result, err := tool.Handler(ctx, userID, arguments)
+	if err != nil {
+		logrus.Warnf("Tool %s failed: %v", call.Name, err)
+		return toolErrorResult(err)
+	}
+
+	logrus.Infof("Tool %s called successfully", call.Name)
+	return result
+}
+
+// toolErrorResult - Helper to encode a tool error as a JSON result for the model
+func toolErrorResult(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
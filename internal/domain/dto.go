@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,7 @@ type (
 		Date        *string     `json:"date"`
 		Image       *string     `json:"image"`
 		Status      *TodoStatus `json:"status"`
+		OwnerID     *string     `json:"owner_id"`
 	}
 
 	// QueryTodoRequest struct - Domain query request DTO
@@ -26,6 +28,7 @@ type (
 		Title       *string
 		Description *string
 		Status      *string
+		OwnerID     *string
 
 		Limit      *int
 		Page       *int
@@ -55,6 +58,7 @@ type (
 		Date        *string         `json:"date,omitempty"`
 		Image       *string         `json:"image,omitempty"`
 		Status      *TodoStatus     `json:"status,omitempty"`
+		OwnerID     *string         `json:"owner_id,omitempty"`
 		CreatedAt   *time.Time      `json:"created_at,omitempty"`
 		UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
 		DeletedAt   *gorm.DeletedAt `json:"deleted_at,omitempty"`
@@ -85,12 +89,68 @@ type (
 		Messages []LineOutgoingMessage
 	}
 
+	// LineBroadcastRequest struct - Domain LINE broadcast message request DTO
+	// Broadcast messages are sent to every user who has added the bot as a friend.
+	LineBroadcastRequest struct {
+		Messages []LineOutgoingMessage
+	}
+
 	// LineOutgoingMessage struct - Domain LINE outgoing message DTO
 	LineOutgoingMessage struct {
-		Type      LineMessageType
-		Text      string
-		PackageID string // For sticker
-		StickerID string // For sticker
+		Type               LineMessageType
+		Text               string
+		PackageID          string               // For sticker
+		StickerID          string               // For sticker
+		OriginalContentURL string               // For image, video, and audio (HTTPS)
+		PreviewImageURL    string               // For image and video (HTTPS)
+		Duration           int64                // For audio, length in milliseconds
+		Title              string               // For location
+		Address            string               // For location
+		Latitude           float64              // For location
+		Longitude          float64              // For location
+		AltText            string               // For template and flex, shown in notifications and chat lists
+		Template           *LineTemplate        // For template
+		FlexContents       json.RawMessage      // For flex, a bubble or carousel container as JSON
+		QuickReply         []LineQuickReplyItem // Optional for any type, up to 13 buttons
+	}
+
+	// LineTemplate struct - Domain LINE template DTO (buttons, confirm, carousel)
+	LineTemplate struct {
+		Type              LineTemplateType
+		ThumbnailImageURL string               // For buttons (optional)
+		Title             string               // For buttons (optional)
+		Text              string               // For buttons and confirm
+		Actions           []LineAction         // For buttons and confirm
+		Columns           []LineTemplateColumn // For carousel
+	}
+
+	// LineTemplateColumn struct - Domain LINE carousel template column DTO
+	LineTemplateColumn struct {
+		ThumbnailImageURL string // Optional
+		Title             string // Optional
+		Text              string
+		Actions           []LineAction // Every column must have the same number of actions
+	}
+
+	// LineAction struct - Domain LINE action DTO for template buttons and quick reply items
+	LineAction struct {
+		Type  LineActionType
+		Label string
+		Text  string // For message, or the text shown as the user's message for postback (optional)
+		Data  string // For postback
+		URI   string // For uri
+	}
+
+	// LineQuickReplyItem struct - Domain LINE quick reply button DTO
+	LineQuickReplyItem struct {
+		ImageURL string // Icon shown on the button (optional)
+		Action   LineAction
+	}
+
+	// LineMessageContent struct - Domain LINE message content DTO (image, video, audio, file)
+	LineMessageContent struct {
+		Data        []byte
+		ContentType string
 	}
 
 	// LineMessageResponse struct - Domain LINE API response DTO
@@ -112,30 +172,72 @@ const (
 	ChatMessageRoleUser ChatMessageRole = "user"
 	// ChatMessageRoleAssistant - Assistant message role
 	ChatMessageRoleAssistant ChatMessageRole = "assistant"
+	// ChatMessageRoleTool - Tool result message role, answers an assistant tool call
+	ChatMessageRoleTool ChatMessageRole = "tool"
+)
+
+// ChatContentPartType represents the type of a multimodal chat message part
+type ChatContentPartType string
+
+const (
+	// ChatContentPartTypeText - Text content part
+	ChatContentPartTypeText ChatContentPartType = "text"
+	// ChatContentPartTypeImageURL - Image content part, given as a URL or base64 data URL
+	ChatContentPartTypeImageURL ChatContentPartType = "image_url"
 )
 
 type (
 	// ChatMessage struct - Domain chat message DTO for LM Studio
+	// Content holds plain text. Parts, when set, carry multimodal content (text and images)
+	// and take precedence over Content when the message is sent.
+	// ToolCalls are set on assistant messages that call tools; ToolCallID on the tool's result.
 	ChatMessage struct {
-		Role    ChatMessageRole `json:"role"`
-		Content string          `json:"content"`
+		Role       ChatMessageRole   `json:"role"`
+		Content    string            `json:"content"`
+		Parts      []ChatContentPart `json:"parts,omitempty"`
+		ToolCalls  []ChatToolCall    `json:"tool_calls,omitempty"`
+		ToolCallID string            `json:"tool_call_id,omitempty"`
+	}
+
+	// ChatContentPart struct - Domain chat message content part DTO
+	ChatContentPart struct {
+		Type     ChatContentPartType `json:"type"`
+		Text     string              `json:"text,omitempty"`      // For text
+		ImageURL string              `json:"image_url,omitempty"` // For image_url, e.g. "data:image/jpeg;base64,..."
+	}
+
+	// ChatToolDefinition struct - Domain function tool the model may call
+	ChatToolDefinition struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"` // JSON Schema of the arguments object
+	}
+
+	// ChatToolCall struct - Domain function call requested by the model
+	ChatToolCall struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON object, as generated by the model
 	}
 
 	// ChatCompletionRequest struct - Domain chat completion request DTO
 	ChatCompletionRequest struct {
-		Messages    []ChatMessage `json:"messages"`
-		Model       *string       `json:"model,omitempty"`
-		Stream      bool          `json:"stream"`
-		Temperature *float64      `json:"temperature,omitempty"`
+		Messages    []ChatMessage        `json:"messages"`
+		Model       *string              `json:"model,omitempty"`
+		Stream      bool                 `json:"stream"`
+		Temperature *float64             `json:"temperature,omitempty"`
+		Tools       []ChatToolDefinition `json:"tools,omitempty"`
 	}
 
 	// ChatCompletionResponse struct - Domain chat completion response DTO
+	// ToolCalls is set instead of Content when the model asks for tools to be called.
 	ChatCompletionResponse struct {
-		Content          string `json:"content"`
-		Model            string `json:"model"`
-		PromptTokens     int    `json:"prompt_tokens"`
-		CompletionTokens int    `json:"completion_tokens"`
-		TotalTokens      int    `json:"total_tokens"`
+		Content          string         `json:"content"`
+		ToolCalls        []ChatToolCall `json:"tool_calls,omitempty"`
+		Model            string         `json:"model"`
+		PromptTokens     int            `json:"prompt_tokens"`
+		CompletionTokens int            `json:"completion_tokens"`
+		TotalTokens      int            `json:"total_tokens"`
 	}
 
 	// ChatCompletionChunk struct - Domain chat completion chunk DTO for streaming
@@ -145,10 +247,42 @@ type (
 		Error   error  `json:"-"`
 	}
 
+	// TranscriptionRequest struct - Domain speech-to-text request DTO
+	TranscriptionRequest struct {
+		Audio       []byte
+		ContentType string // e.g. "audio/m4a"
+		Model       *string
+		Language    string // Optional ISO-639-1 hint, e.g. "th"
+	}
+
+	// TranscriptionResponse struct - Domain speech-to-text response DTO
+	TranscriptionResponse struct {
+		Text string `json:"text"`
+	}
+
 	// ModelInfo struct - Domain model information DTO
 	ModelInfo struct {
 		ID      string `json:"id"`
 		Object  string `json:"object"`
 		OwnedBy string `json:"owned_by"`
 	}
+
+	// CircuitBreakerStatus struct - Domain circuit breaker state DTO for an LLM server
+	CircuitBreakerStatus struct {
+		State               CircuitState `json:"state"`
+		ConsecutiveFailures int          `json:"consecutive_failures"`
+		OpenedAt            *time.Time   `json:"opened_at,omitempty"` // When the circuit last opened, nil while closed
+	}
+)
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitStateClosed - Requests flow normally
+	CircuitStateClosed CircuitState = "closed"
+	// CircuitStateOpen - Requests fail fast until the cooldown has passed
+	CircuitStateOpen CircuitState = "open"
+	// CircuitStateHalfOpen - A probe request is let through to test whether the server is back
+	CircuitStateHalfOpen CircuitState = "half_open"
 )
@@ -11,6 +11,33 @@ var (
 	// ErrLMStudioTimeout indicates a request to LM Studio timed out
 	ErrLMStudioTimeout = errors.New("lm studio request timeout")
 
+	// ErrLMStudioBusy indicates all LM Studio request slots stayed busy for longer than the wait limit
+	ErrLMStudioBusy = errors.New("lm studio at capacity")
+
 	// ErrInvalidRequest indicates an invalid request was made (4xx client errors)
 	ErrInvalidRequest = errors.New("invalid request")
 )
+
+// Transcription error types
+
+var (
+	// ErrTranscriptionUnavailable indicates the speech-to-text service is unavailable
+	ErrTranscriptionUnavailable = errors.New("transcription service unavailable")
+)
+
+// LINE error types
+
+var (
+	// ErrLineContentTooLarge indicates message content exceeded the size the bot is willing to download
+	ErrLineContentTooLarge = errors.New("line message content too large")
+)
+
+// Webhook processing error types
+
+var (
+	// ErrWebhookQueueFull indicates the webhook job queue has no capacity for more events
+	ErrWebhookQueueFull = errors.New("webhook queue full")
+
+	// ErrWebhookDispatcherClosed indicates the webhook dispatcher has been shut down
+	ErrWebhookDispatcherClosed = errors.New("webhook dispatcher closed")
+)
@@ -38,6 +38,40 @@ const (
 	LineMessageTypeLocation LineMessageType = "location"
 	// LineMessageTypeSticker - Sticker message
 	LineMessageTypeSticker LineMessageType = "sticker"
+	// LineMessageTypeTemplate - Template message (outgoing only)
+	LineMessageTypeTemplate LineMessageType = "template"
+	// LineMessageTypeFlex - Flex message (outgoing only)
+	LineMessageTypeFlex LineMessageType = "flex"
+)
+
+// LineTemplateType represents the layout of a template message
+type LineTemplateType string
+
+const (
+	// LineTemplateTypeButtons - Buttons template with an optional image, title, and up to 4 actions
+	LineTemplateTypeButtons LineTemplateType = "buttons"
+	// LineTemplateTypeConfirm - Confirm template with exactly 2 actions
+	LineTemplateTypeConfirm LineTemplateType = "confirm"
+	// LineTemplateTypeCarousel - Carousel template with up to 10 columns
+	LineTemplateTypeCarousel LineTemplateType = "carousel"
+)
+
+// LineActionType represents the type of an action on a template button or quick reply item
+type LineActionType string
+
+const (
+	// LineActionTypeMessage - Sends Text as a message from the user
+	LineActionTypeMessage LineActionType = "message"
+	// LineActionTypePostback - Returns Data in a postback event
+	LineActionTypePostback LineActionType = "postback"
+	// LineActionTypeURI - Opens URI
+	LineActionTypeURI LineActionType = "uri"
+	// LineActionTypeLocation - Opens the location screen (quick reply only)
+	LineActionTypeLocation LineActionType = "location"
+	// LineActionTypeCamera - Opens the camera (quick reply only)
+	LineActionTypeCamera LineActionType = "camera"
+	// LineActionTypeCameraRoll - Opens the camera roll (quick reply only)
+	LineActionTypeCameraRoll LineActionType = "cameraRoll"
 )
 
 // LineSourceType represents the source type of the event
@@ -54,12 +88,14 @@ const (
 
 // LineWebhookEvent represents a LINE webhook event (domain entity)
 type LineWebhookEvent struct {
-	ID         string
-	Type       LineEventType
-	Timestamp  time.Time
-	Source     LineSource
-	ReplyToken string
-	Message    *LineMessage
+	ID           string // LINE webhookEventId, unique per event across redeliveries
+	Type         LineEventType
+	Timestamp    time.Time
+	Source       LineSource
+	ReplyToken   string
+	Message      *LineMessage
+	Postback     *LinePostback
+	IsRedelivery bool // True if LINE is resending an event it could not confirm as delivered
 }
 
 // LineSource represents the source of the event
@@ -72,9 +108,22 @@ type LineSource struct {
 
 // LineMessage represents a message from LINE
 type LineMessage struct {
-	ID        string
-	Type      LineMessageType
-	Text      string
-	PackageID string // For sticker
-	StickerID string // For sticker
+	ID          string
+	Type        LineMessageType
+	Text        string
+	PackageID   string   // For sticker
+	StickerID   string   // For sticker
+	Keywords    []string // For sticker, e.g. "Happy", "Thanks" (not set for every sticker)
+	Duration    int64    // For audio and video, length in milliseconds
+	Title       string   // For location, e.g. place name (optional)
+	Address     string   // For location (optional)
+	Latitude    float64  // For location
+	Longitude   float64  // For location
+	MentionsBot bool     // For text in groups and rooms: the bot itself was @mentioned
+}
+
+// LinePostback represents the payload of a postback action (button, quick reply, rich menu)
+type LinePostback struct {
+	Data   string            // Data set on the action, e.g. "action=confirm&id=42"
+	Params map[string]string // Values chosen in datetime picker or rich menu switch actions
 }
@@ -1,12 +1,19 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// conversationSummaryPrefix marks the rolling summary system message kept at the start of a session
+const conversationSummaryPrefix = "Summary of the earlier conversation: "
 
 // ConversationSession represents a conversation session for a LINE user
 type ConversationSession struct {
 	UserID         string        // LINE user identifier
 	Messages       []ChatMessage // Conversation history
 	LastAccessTime time.Time     // For session expiration checking
+	Persona        string        // Name of the active persona, empty for the default system prompt
 	timeout        time.Duration // Configurable session timeout
 	maxTurns       int           // Configurable maximum conversation turns
 }
@@ -29,18 +36,76 @@ func (s *ConversationSession) IsExpired() bool {
 }
 
 // AddTurn adds a user message and assistant response to the conversation
-// If the history limit is reached, the oldest turn (2 messages) is removed
+// If the history limit is reached, the oldest turn (2 messages) is removed.
+// The rolling summary message, if any, is kept.
 func (s *ConversationSession) AddTurn(userMsg, assistantMsg ChatMessage) {
 	// Check if at maximum turns and remove oldest turn if needed
-	if len(s.Messages) >= s.maxTurns*2 {
-		// Remove the oldest turn (first 2 messages)
-		s.Messages = s.Messages[2:]
+	if s.TurnCount() >= s.maxTurns {
+		// Remove the oldest turn (first 2 messages after the summary)
+		s.TakeOldestTurns(1)
 	}
 
 	// Add the new turn
 	s.Messages = append(s.Messages, userMsg, assistantMsg)
 }
 
+// TurnCount returns the number of conversation turns, not counting the rolling summary
+func (s *ConversationSession) TurnCount() int {
+	return (len(s.Messages) - s.summaryLen()) / 2
+}
+
+// IsFull reports whether adding another turn would remove the oldest one
+func (s *ConversationSession) IsFull() bool {
+	return s.TurnCount() >= s.maxTurns
+}
+
+// TakeOldestTurns removes up to n of the oldest turns and returns their messages.
+// The rolling summary message, if any, is kept.
+func (s *ConversationSession) TakeOldestTurns(n int) []ChatMessage {
+	start := s.summaryLen()
+	end := start + n*2
+	if end > len(s.Messages) {
+		end = len(s.Messages)
+	}
+
+	taken := make([]ChatMessage, end-start)
+	copy(taken, s.Messages[start:end])
+	s.Messages = append(s.Messages[:start], s.Messages[end:]...)
+
+	return taken
+}
+
+// Summary returns the rolling summary of earlier turns, or an empty string if there is none
+func (s *ConversationSession) Summary() string {
+	if s.summaryLen() == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(s.Messages[0].Content, conversationSummaryPrefix)
+}
+
+// SetSummary stores the rolling summary as a system message at the start of the history
+func (s *ConversationSession) SetSummary(summary string) {
+	msg := ChatMessage{
+		Role:    ChatMessageRoleSystem,
+		Content: conversationSummaryPrefix + summary,
+	}
+
+	if s.summaryLen() > 0 {
+		s.Messages[0] = msg
+		return
+	}
+	s.Messages = append([]ChatMessage{msg}, s.Messages...)
+}
+
+// summaryLen returns 1 if the history starts with the rolling summary message, otherwise 0
+func (s *ConversationSession) summaryLen() int {
+	if len(s.Messages) > 0 && s.Messages[0].Role == ChatMessageRoleSystem &&
+		strings.HasPrefix(s.Messages[0].Content, conversationSummaryPrefix) {
+		return 1
+	}
+	return 0
+}
+
 // GetHistory returns a copy of the conversation history
 func (s *ConversationSession) GetHistory() []ChatMessage {
 	if len(s.Messages) == 0 {
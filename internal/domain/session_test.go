@@ -270,3 +270,88 @@ func TestSessionWithCustomTimeoutAndMaxTurnsBehavesCorrectly(t *testing.T) {
 		}
 	})
 }
+
+// TestConversationSessionSummary tests that the rolling summary is stored as a leading system message
+func TestConversationSessionSummary(t *testing.T) {
+	session := NewConversationSession("U1234567890abcdef", defaultTimeout, 2)
+
+	if session.Summary() != "" {
+		t.Errorf("expected no summary on a new session, got %q", session.Summary())
+	}
+
+	session.AddTurn(
+		ChatMessage{Role: ChatMessageRoleUser, Content: "Q1"},
+		ChatMessage{Role: ChatMessageRoleAssistant, Content: "A1"},
+	)
+	session.SetSummary("user asked Q0")
+	session.SetSummary("user asked Q0 and Q1")
+
+	if session.Summary() != "user asked Q0 and Q1" {
+		t.Errorf("expected summary to be replaced, got %q", session.Summary())
+	}
+	if len(session.Messages) != 3 {
+		t.Fatalf("expected summary + 1 turn (3 messages), got %d", len(session.Messages))
+	}
+	if session.Messages[0].Role != ChatMessageRoleSystem {
+		t.Errorf("expected summary to be a system message, got %s", session.Messages[0].Role)
+	}
+	if session.TurnCount() != 1 {
+		t.Errorf("expected summary not to count as a turn, got %d turns", session.TurnCount())
+	}
+}
+
+// TestAddTurnKeepsSummaryWhenTrimming tests that the oldest turn is removed but the summary is kept
+func TestAddTurnKeepsSummaryWhenTrimming(t *testing.T) {
+	session := NewConversationSession("U1234567890abcdef", defaultTimeout, 2)
+	session.SetSummary("earlier context")
+
+	for _, n := range []string{"1", "2", "3"} {
+		session.AddTurn(
+			ChatMessage{Role: ChatMessageRoleUser, Content: "Q" + n},
+			ChatMessage{Role: ChatMessageRoleAssistant, Content: "A" + n},
+		)
+	}
+
+	if session.Summary() != "earlier context" {
+		t.Errorf("expected summary to be kept, got %q", session.Summary())
+	}
+	if session.TurnCount() != 2 {
+		t.Fatalf("expected 2 turns, got %d", session.TurnCount())
+	}
+	if session.Messages[1].Content != "Q2" {
+		t.Errorf("expected oldest turn Q1 to be removed, first turn is %q", session.Messages[1].Content)
+	}
+}
+
+// TestTakeOldestTurns tests that the oldest turns are removed and returned
+func TestTakeOldestTurns(t *testing.T) {
+	session := NewConversationSession("U1234567890abcdef", defaultTimeout, 3)
+	session.SetSummary("earlier context")
+	for _, n := range []string{"1", "2", "3"} {
+		session.AddTurn(
+			ChatMessage{Role: ChatMessageRoleUser, Content: "Q" + n},
+			ChatMessage{Role: ChatMessageRoleAssistant, Content: "A" + n},
+		)
+	}
+
+	if !session.IsFull() {
+		t.Error("expected session at maxTurns to be full")
+	}
+
+	taken := session.TakeOldestTurns(2)
+
+	if len(taken) != 4 || taken[0].Content != "Q1" || taken[3].Content != "A2" {
+		t.Errorf("expected turns Q1..A2 to be taken, got %+v", taken)
+	}
+	if session.TurnCount() != 1 || session.Messages[1].Content != "Q3" {
+		t.Errorf("expected only turn Q3 to remain after the summary, got %+v", session.Messages)
+	}
+
+	// Taking more turns than exist returns what is left
+	if taken := session.TakeOldestTurns(5); len(taken) != 2 {
+		t.Errorf("expected remaining 2 messages, got %d", len(taken))
+	}
+	if session.Summary() != "earlier context" {
+		t.Errorf("expected summary to be kept, got %q", session.Summary())
+	}
+}
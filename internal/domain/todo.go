@@ -26,6 +26,7 @@ type Todo struct {
 	Date        *time.Time      `gorm:"type:timestamp;not null;"`
 	Image       *string         `gorm:"type:text"`
 	Status      *TodoStatus     `gorm:"type:varchar(11);not null;"`
+	OwnerID     *string         `gorm:"type:varchar(64);index"` // LINE user ID, nil for todos created via the REST API
 	CreatedAt   *time.Time      `gorm:"type:timestamp"`
 	UpdatedAt   *time.Time      `gorm:"type:timestamp"`
 	DeletedAt   *gorm.DeletedAt `gorm:"type:timestamp"`
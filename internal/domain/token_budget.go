@@ -0,0 +1,27 @@
+package domain
+
+// TokenBudget represents the context window limits used to trim conversation history
+type TokenBudget struct {
+	ContextTokens      int            // Default context window size in tokens
+	ReplyTokens        int            // Tokens reserved for the model's reply
+	ModelContextTokens map[string]int // Context window overrides keyed by model name
+}
+
+// PromptTokens returns the number of tokens available for the prompt (system prompt,
+// history, and new message) when talking to the given model.
+// Returns 0 if no budget is configured, meaning the prompt is not limited.
+func (b TokenBudget) PromptTokens(model string) int {
+	contextTokens := b.ContextTokens
+	if modelTokens, ok := b.ModelContextTokens[model]; ok && modelTokens > 0 {
+		contextTokens = modelTokens
+	}
+	if contextTokens <= 0 {
+		return 0
+	}
+
+	promptTokens := contextTokens - b.ReplyTokens
+	if promptTokens < 1 {
+		promptTokens = 1
+	}
+	return promptTokens
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "testing"
+
+// TestTokenBudgetPromptTokens tests prompt budget resolution for default and per-model context sizes
+func TestTokenBudgetPromptTokens(t *testing.T) {
+	budget := TokenBudget{
+		ContextTokens:      4096,
+		ReplyTokens:        512,
+		ModelContextTokens: map[string]int{"qwen2.5-7b-instruct": 32768},
+	}
+
+	tests := []struct {
+		name     string
+		budget   TokenBudget
+		model    string
+		expected int
+	}{
+		{name: "default model", budget: budget, model: "llama-3.2-3b-instruct", expected: 3584},
+		{name: "unset model uses default", budget: budget, model: "", expected: 3584},
+		{name: "model override", budget: budget, model: "qwen2.5-7b-instruct", expected: 32256},
+		{name: "no budget configured", budget: TokenBudget{}, model: "any", expected: 0},
+		{name: "reply reserve larger than context", budget: TokenBudget{ContextTokens: 100, ReplyTokens: 200}, model: "", expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.budget.PromptTokens(tt.model); got != tt.expected {
+				t.Errorf("expected %d prompt tokens, got %d", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package output
+
+// EventDedupStore interface - Output port
+// Defines what the application needs to recognize LINE webhook events it has already
+// processed. LINE may deliver the same event more than once (for example when a
+// webhook response is lost), identified by the same webhookEventId.
+// Implementations must be thread-safe for concurrent access.
+type EventDedupStore interface {
+	// MarkSeen atomically records the webhook event ID and reports whether it had
+	// already been recorded. Records are kept for an implementation-defined retention
+	// period, after which the same ID is treated as new again.
+	// Returns an error only if there is a storage access failure.
+	MarkSeen(eventID string) (bool, error)
+}
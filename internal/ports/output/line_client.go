@@ -11,6 +11,12 @@ type LineClient interface {
 	// PushMessage sends push messages to LINE user directly
 	PushMessage(request domain.LinePushMessageRequest) (*domain.LineMessageResponse, error)
 
+	// BroadcastMessage sends messages to every user who has added the bot as a friend
+	BroadcastMessage(request domain.LineBroadcastRequest) (*domain.LineMessageResponse, error)
+
+	// GetMessageContent downloads the content of an image, video, audio, or file message
+	GetMessageContent(messageID string) (*domain.LineMessageContent, error)
+
 	// GetProfile gets user profile information
 	GetProfile(userID string) (interface{}, error)
 }
@@ -0,0 +1,8 @@
+package output
+
+// RateLimiter interface - Output port
+// Defines how the application throttles LLM-backed replies per LINE user.
+type RateLimiter interface {
+	// Allow consumes one request from the key's allowance and reports whether the request may proceed.
+	Allow(key string) (bool, error)
+}
@@ -26,3 +26,24 @@ type SessionStore interface {
 	// Returns an error only if there is a storage access failure.
 	DeleteSession(userID string) error
 }
+
+// SessionTurnAppender interface - Optional output port extension
+// Implemented by session stores that can append a turn atomically on the storage side.
+// When several replicas serve the same user, a read-modify-write through GetSession and
+// UpdateSession can lose turns; appending on the storage side cannot.
+type SessionTurnAppender interface {
+	// AppendTurn atomically appends a user message and assistant response to the user's
+	// session, creating the session if it does not exist, trimming the history to the
+	// configured maximum turns, and refreshing the session expiry.
+	// Returns an error if the turn cannot be stored.
+	AppendTurn(userID string, userMsg, assistantMsg domain.ChatMessage) error
+}
+
+// SessionCounter interface - Optional output port extension
+// Implemented by session stores that can report how many conversations are active,
+// used by the /stats admin command.
+type SessionCounter interface {
+	// CountSessions returns the number of sessions that have not expired.
+	// Returns an error only if there is a storage access failure.
+	CountSessions() (int, error)
+}
@@ -0,0 +1,10 @@
+package output
+
+// TokenEstimator interface - Output port
+// Estimates how many model tokens a piece of text consumes, so conversation history
+// can be trimmed to fit the model's context window before a request is sent.
+type TokenEstimator interface {
+	// EstimateTokens returns the estimated number of tokens in text.
+	// Estimates should err on the high side; underestimating lets requests overflow the context.
+	EstimateTokens(text string) int
+}
@@ -0,0 +1,15 @@
+package output
+
+import (
+	"context"
+
+	"golang-template/internal/domain"
+)
+
+// Transcriber interface - Output port
+// Defines what the application needs from a speech-to-text service
+type Transcriber interface {
+	// Transcribe converts recorded speech to text.
+	// Returns domain.ErrTranscriptionUnavailable if the service can't be reached or fails.
+	Transcribe(ctx context.Context, request domain.TranscriptionRequest) (*domain.TranscriptionResponse, error)
+}
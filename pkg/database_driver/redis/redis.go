@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// connectTimeout bounds the initial PING to the server
+const connectTimeout = 5 * time.Second
+
+// ConnectToRedis func
+func ConnectToRedis(addr, password string, db int) (*goredis.Client, error) {
+	if addr == "" {
+		return nil, errors.New("cannot estabished the connection")
+	}
+
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		logrus.Error(err)
+		_ = client.Close()
+		return nil, err
+	}
+
+	logrus.Info("Connected to redis: ", addr)
+	return client, nil
+}
+
+// DisconnectRedis func
+func DisconnectRedis(client *goredis.Client) {
+	if err := client.Close(); err != nil {
+		logrus.Error(err)
+	}
+	logrus.Println("Connected with redis has closed")
+}
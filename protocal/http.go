@@ -1,26 +1,44 @@
 package protocal
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"golang-template/configs"
 	httpAdapter "golang-template/internal/adapters/input/http"
 	lineAdapter "golang-template/internal/adapters/output/line"
 	lmstudioAdapter "golang-template/internal/adapters/output/lmstudio"
 	memoryAdapter "golang-template/internal/adapters/output/memory"
+	ollamaAdapter "golang-template/internal/adapters/output/ollama"
 	"golang-template/internal/adapters/output/postgres"
+	redisAdapter "golang-template/internal/adapters/output/redis"
+	routingAdapter "golang-template/internal/adapters/output/routing"
+	"golang-template/internal/adapters/output/tokenizer"
+	transcriptionAdapter "golang-template/internal/adapters/output/transcription"
 	"golang-template/internal/application"
+	"golang-template/internal/domain"
+	"golang-template/internal/ports/output"
 	"golang-template/pkg/database_driver/gorm"
+	"golang-template/pkg/database_driver/redis"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
+// webhookDrainTimeout is how long shutdown waits for queued webhook events
+const webhookDrainTimeout = 30 * time.Second
+
 type config struct {
 	ENV string `mapstructure:"env"`
 }
@@ -54,7 +72,6 @@ func ServeHTTP() error {
 	go func() {
 		for range c {
 			log.Println("Gracefull shut down ...")
-			gorm.DisconnectPostgres(dbConGorm.Postgres)
 			err := app.Shutdown()
 			if err != nil {
 				log.Println("Error when shutdown server: ", err)
@@ -67,8 +84,6 @@ func ServeHTTP() error {
 	postgresRepo := postgres.NewTodoRepository(dbConGorm.Postgres)
 	// Application service (use case)
 	srv := application.NewTodoService(postgresRepo)
-	// Input adapter (HTTP handler)
-	hdl := httpAdapter.New(srv, dbConGorm.Postgres)
 
 	// Wire up LINE hexagonal architecture
 	// Output adapter (LINE client)
@@ -77,17 +92,69 @@ func ServeHTTP() error {
 		logrus.Fatalf("Failed to create LINE client: %v", err)
 	}
 
-	// Output adapter (LM Studio client)
-	lmStudioClient, err := lmstudioAdapter.NewLMStudioClientAdapter(configs.GetViper().LMStudio)
-	if err != nil {
-		logrus.Fatalf("Failed to create LM Studio client: %v", err)
+	// Output adapter (LM Studio or Ollama client), or a router over several backends when configured
+	var lmStudioClient output.LMStudioClient
+	var backendRouter *routingAdapter.RoutingClientAdapter
+	// Circuit breakers of the LLM clients, reported on the health endpoint
+	llmCircuitBreakers := make(map[string]httpAdapter.CircuitBreakerReporter)
+	provider := stripPlaceholder(configs.GetViper().LMStudio.Provider, "LMSTUDIO_PROVIDER")
+	backendConfigs := parseBackends(stripPlaceholder(configs.GetViper().LMStudio.Backends, "LMSTUDIO_BACKENDS"))
+	if len(backendConfigs) > 0 {
+		backends, err := newBackends(configs.GetViper().LMStudio, backendConfigs)
+		if err != nil {
+			logrus.Fatalf("Failed to create LLM backends: %v", err)
+		}
+		backendRouter, err = routingAdapter.NewRoutingClientAdapter(backends)
+		if err != nil {
+			logrus.Fatalf("Failed to create LLM backend router: %v", err)
+		}
+		for _, backend := range backends {
+			if reporter, ok := backend.Client.(httpAdapter.CircuitBreakerReporter); ok {
+				llmCircuitBreakers[backend.Name] = reporter
+			}
+		}
+		healthCheckInterval := 30 * time.Second // default health check interval
+		if configs.GetViper().LMStudio.HealthCheckInterval > 0 {
+			healthCheckInterval = time.Duration(configs.GetViper().LMStudio.HealthCheckInterval) * time.Second
+		}
+		backendRouter.StartHealthChecks(healthCheckInterval)
+		logrus.Infof("LLM backends: backends=%d, healthCheckInterval=%v", len(backendConfigs), healthCheckInterval)
+		lmStudioClient = backendRouter
+	} else {
+		lmStudioClient, err = newLLMClient(provider, configs.GetViper().LMStudio)
+		if err != nil {
+			logrus.Fatalf("Failed to create LLM client: %v", err)
+		}
+		logrus.Infof("LLM provider: %s", providerName(provider))
+		if reporter, ok := lmStudioClient.(httpAdapter.CircuitBreakerReporter); ok {
+			llmCircuitBreakers[providerName(provider)] = reporter
+		}
+	}
+
+	// Speech-to-text for voice messages, disabled when no server is configured
+	var transcriber output.Transcriber
+	transcriptionConfig := configs.GetViper().Transcription
+	transcriptionConfig.BaseURL = stripPlaceholder(transcriptionConfig.BaseURL, "TRANSCRIPTION_BASE_URL")
+	transcriptionConfig.Model = stripPlaceholder(transcriptionConfig.Model, "TRANSCRIPTION_MODEL")
+	transcriptionConfig.Language = stripPlaceholder(transcriptionConfig.Language, "TRANSCRIPTION_LANGUAGE")
+	if transcriptionConfig.BaseURL != "" {
+		transcriptionClient, err := transcriptionAdapter.NewOpenAITranscriberAdapter(transcriptionConfig)
+		if err != nil {
+			logrus.Fatalf("Failed to create transcription client: %v", err)
+		}
+		transcriber = transcriptionClient
+	} else {
+		logrus.Info("Transcription disabled: voice messages will be ignored")
 	}
 
 	// Read session config with defaults
-	// Default values: timeout=30 minutes, maxTurns=10
+	// Default values: timeout=30 minutes, maxTurns=10, driver=memory, maxSessions=10000, cleanupInterval=5 minutes
 	sessionConfig := configs.GetViper().Session
-	sessionTimeout := 30 * time.Minute // default timeout
-	sessionMaxTurns := 10              // default max turns
+	sessionTimeout := 30 * time.Minute        // default timeout
+	sessionMaxTurns := 10                     // default max turns
+	sessionDriver := "memory"                 // default driver
+	sessionMaxSessions := 10000               // default in-memory session cap
+	sessionCleanupInterval := 5 * time.Minute // default janitor interval
 
 	if sessionConfig.Timeout > 0 {
 		sessionTimeout = time.Duration(sessionConfig.Timeout) * time.Minute
@@ -95,11 +162,42 @@ func ServeHTTP() error {
 	if sessionConfig.MaxTurns > 0 {
 		sessionMaxTurns = sessionConfig.MaxTurns
 	}
+	if sessionConfig.Driver == "postgres" || sessionConfig.Driver == "redis" {
+		sessionDriver = sessionConfig.Driver
+	}
+	if sessionConfig.MaxSessions > 0 {
+		sessionMaxSessions = sessionConfig.MaxSessions
+	}
+	if sessionConfig.CleanupInterval > 0 {
+		sessionCleanupInterval = time.Duration(sessionConfig.CleanupInterval) * time.Minute
+	}
 
-	logrus.Infof("Session config: timeout=%v, maxTurns=%d", sessionTimeout, sessionMaxTurns)
+	logrus.Infof("Session config: timeout=%v, maxTurns=%d, driver=%s, summarize=%t",
+		sessionTimeout, sessionMaxTurns, sessionDriver, sessionConfig.Summarize)
 
-	// Output adapter (Memory session store for conversation context)
-	sessionStore := memoryAdapter.NewMemorySessionStore(sessionTimeout, sessionMaxTurns)
+	// Output adapter (session store for conversation context)
+	var sessionStore output.SessionStore
+	var redisClient *goredis.Client
+	var memorySessionStore *memoryAdapter.MemorySessionStore
+	switch sessionDriver {
+	case "postgres":
+		sessionStore = postgres.NewPostgresSessionStore(dbConGorm.Postgres, sessionTimeout, sessionMaxTurns)
+	case "redis":
+		redisConfig := configs.GetViper().Redis
+		redisPassword := stripPlaceholder(redisConfig.Password, "REDIS_PASSWORD")
+		redisClient, err = redis.ConnectToRedis(redisConfig.Addr, redisPassword, redisConfig.DB)
+		if err != nil {
+			logrus.Fatalf("Failed to connect to redis: %v", err)
+		}
+		sessionStore = redisAdapter.NewRedisSessionStore(redisClient, sessionTimeout, sessionMaxTurns)
+	default:
+		memorySessionStore = memoryAdapter.NewMemorySessionStore(sessionTimeout, sessionMaxTurns,
+			memoryAdapter.WithMaxSessions(sessionMaxSessions))
+		// Sweep sessions of users who never come back; stopped after the server shuts down
+		memorySessionStore.StartJanitor(sessionCleanupInterval)
+		logrus.Infof("Memory session store: maxSessions=%d, cleanupInterval=%v", sessionMaxSessions, sessionCleanupInterval)
+		sessionStore = memorySessionStore
+	}
 
 	// Get system prompt from config with default fallback
 	systemPrompt := configs.GetViper().LMStudio.SystemPrompt
@@ -108,10 +206,143 @@ func ServeHTTP() error {
 	}
 	logrus.Infof("Using system prompt: %s", systemPrompt)
 
+	// Read token budget config with defaults
+	// Default values: contextTokens=4096, replyTokens=512
+	lmStudioConfig := configs.GetViper().LMStudio
+	tokenBudget := domain.TokenBudget{
+		ContextTokens:      4096, // default context window
+		ReplyTokens:        512,  // default reply reserve
+		ModelContextTokens: parseModelContextTokens(stripPlaceholder(lmStudioConfig.ModelContextTokens, "LMSTUDIO_MODEL_CONTEXT_TOKENS")),
+	}
+	if lmStudioConfig.ContextTokens > 0 {
+		tokenBudget.ContextTokens = lmStudioConfig.ContextTokens
+	}
+	if lmStudioConfig.ReplyTokens > 0 {
+		tokenBudget.ReplyTokens = lmStudioConfig.ReplyTokens
+	}
+
+	logrus.Infof("Token budget: contextTokens=%d, replyTokens=%d, modelContextTokens=%v",
+		tokenBudget.ContextTokens, tokenBudget.ReplyTokens, tokenBudget.ModelContextTokens)
+
+	// Read webhook config with defaults
+	// Default values: workers=4, queueSize=100, dedupDriver=memory, dedupTTL=24 hours
+	webhookConfig := configs.GetViper().Webhook
+	webhookWorkers := 4               // default workers
+	webhookQueueSize := 100           // default queue size per worker
+	webhookDedupDriver := "memory"    // default dedup driver
+	webhookDedupTTL := 24 * time.Hour // default dedup TTL
+
+	if webhookConfig.Workers > 0 {
+		webhookWorkers = webhookConfig.Workers
+	}
+	if webhookConfig.QueueSize > 0 {
+		webhookQueueSize = webhookConfig.QueueSize
+	}
+	if webhookConfig.DedupDriver == "postgres" {
+		webhookDedupDriver = webhookConfig.DedupDriver
+	}
+	if webhookConfig.DedupTTL > 0 {
+		webhookDedupTTL = time.Duration(webhookConfig.DedupTTL) * time.Minute
+	}
+
+	logrus.Infof("Webhook config: workers=%d, queueSize=%d, dedupDriver=%s, dedupTTL=%v",
+		webhookWorkers, webhookQueueSize, webhookDedupDriver, webhookDedupTTL)
+
+	// Output adapter (webhook event dedup store)
+	var dedupStore output.EventDedupStore
+	if webhookDedupDriver == "postgres" {
+		dedupStore = postgres.NewEventDedupRepository(dbConGorm.Postgres, webhookDedupTTL)
+	} else {
+		dedupStore = memoryAdapter.NewMemoryEventDedupStore(webhookDedupTTL)
+	}
+
+	// Read rate limit config with defaults
+	// Default values: userPerMinute=10, userBurst=5, maxConcurrent=2, queueTimeout=30 seconds
+	rateLimitConfig := configs.GetViper().RateLimit
+	rateLimitUserPerMinute := 10              // default per-user refill rate
+	rateLimitUserBurst := 5                   // default per-user burst
+	rateLimitMaxConcurrent := 2               // default concurrent LM Studio requests
+	rateLimitQueueTimeout := 30 * time.Second // default wait for a free slot
+
+	if rateLimitConfig.UserPerMinute > 0 {
+		rateLimitUserPerMinute = rateLimitConfig.UserPerMinute
+	}
+	if rateLimitConfig.UserBurst > 0 {
+		rateLimitUserBurst = rateLimitConfig.UserBurst
+	}
+	if rateLimitConfig.MaxConcurrent > 0 {
+		rateLimitMaxConcurrent = rateLimitConfig.MaxConcurrent
+	}
+	if rateLimitConfig.QueueTimeout > 0 {
+		rateLimitQueueTimeout = time.Duration(rateLimitConfig.QueueTimeout) * time.Second
+	}
+
+	logrus.Infof("Rate limit config: userPerMinute=%d, userBurst=%d, maxConcurrent=%d, queueTimeout=%v",
+		rateLimitUserPerMinute, rateLimitUserBurst, rateLimitMaxConcurrent, rateLimitQueueTimeout)
+
+	// Read sticker config with defaults
+	// Default values: policy=ignore
+	stickerConfig := configs.GetViper().Sticker
+	stickerPolicy := application.StickerPolicyIgnore // default sticker policy
+	switch policy := application.StickerPolicy(strings.ToLower(stickerConfig.Policy)); policy {
+	case application.StickerPolicyLLM, application.StickerPolicyReply:
+		stickerPolicy = policy
+	}
+	stickerReplies := parseStickerReplies(stripPlaceholder(stickerConfig.Replies, "STICKER_REPLIES"))
+
+	logrus.Infof("Sticker config: policy=%s, replies=%d", stickerPolicy, len(stickerReplies))
+
+	// LINE users allowed to run the admin commands, none by default
+	adminUserIDs := parseUserIDs(stripPlaceholder(configs.GetViper().Admin.UserIDs, "ADMIN_USER_IDS"))
+
+	logrus.Infof("Admin config: admins=%d", len(adminUserIDs))
+
+	// Personas users can pick with /persona, none by default
+	personas := parsePersonas(stripPlaceholder(configs.GetViper().Persona.Catalog, "PERSONA_CATALOG"))
+
+	logrus.Infof("Persona config: personas=%d", len(personas))
+
+	// Go-side tools the model may call, disabled for models without function calling
+	toolRegistry := application.NewToolRegistry()
+	if lmStudioConfig.Tools {
+		if err := toolRegistry.Register(application.NewTodoTools(srv)...); err != nil {
+			logrus.Fatalf("Failed to register tools: %v", err)
+		}
+	}
+
+	logrus.Infof("LM Studio tools: %d registered", toolRegistry.Len())
+
+	// Global cap on concurrent LM Studio requests, shared by every user
+	limitedLMStudioClient := application.NewLMStudioConcurrencyLimiter(lmStudioClient, rateLimitMaxConcurrent, rateLimitQueueTimeout)
+
 	// Application service (LINE webhook use case)
-	lineWebhookSrv := application.NewLineWebhookService(lineClient, lmStudioClient, sessionStore, systemPrompt, sessionTimeout, sessionMaxTurns)
+	lineWebhookSrv := application.NewLineWebhookService(
+		lineClient,
+		limitedLMStudioClient,
+		sessionStore,
+		systemPrompt,
+		sessionTimeout,
+		sessionMaxTurns,
+		application.WithStreaming(configs.GetViper().LMStudio.Stream),
+		application.WithVision(lmStudioConfig.Vision),
+		application.WithTranscriber(transcriber),
+		application.WithTools(toolRegistry),
+		application.WithTodoService(srv),
+		application.WithAdmins(adminUserIDs),
+		application.WithPersonas(personas),
+		application.WithStickerPolicy(stickerPolicy, stickerReplies),
+		application.WithEventDedup(dedupStore),
+		application.WithModel(lmStudioConfig.Model),
+		application.WithTokenBudget(tokenizer.NewHeuristicTokenEstimator(), tokenBudget),
+		application.WithSummarization(sessionConfig.Summarize),
+		application.WithRateLimiter(memoryAdapter.NewMemoryRateLimiter(rateLimitUserPerMinute, rateLimitUserBurst)),
+	)
+	// Asynchronous dispatcher so webhooks are acknowledged before LM Studio answers
+	lineWebhookDispatcher := application.NewLineWebhookDispatcher(lineWebhookSrv, webhookWorkers, webhookQueueSize)
 	// Input adapter (LINE webhook handler)
-	lineWebhookHdl := httpAdapter.NewLineWebhookHandler(lineWebhookSrv, configs.GetViper().Line.ChannelSecret)
+	lineWebhookHdl := httpAdapter.NewLineWebhookHandler(lineWebhookDispatcher, configs.GetViper().Line.ChannelSecret)
+	// Input adapter (HTTP handler)
+	hdl := httpAdapter.New(srv, dbConGorm.Postgres, httpAdapter.WithCircuitBreakers(llmCircuitBreakers))
 	app.Get("/swagger/*", swagger.HandlerDefault) // default
 	app.Get("/health", hdl.HealthCheck)
 
@@ -131,6 +362,28 @@ func ServeHTTP() error {
 	}
 
 	err = app.Listen(":" + configs.GetViper().App.Port)
+
+	// Listen returns once the server has shut down; finish queued webhook events
+	// before closing the database connection they may still use
+	drainCtx, cancel := context.WithTimeout(context.Background(), webhookDrainTimeout)
+	defer cancel()
+	if drainErr := lineWebhookDispatcher.Shutdown(drainCtx); drainErr != nil {
+		logrus.Errorf("Error when draining webhook queue: %v", drainErr)
+	}
+	if backendRouter != nil {
+		backendRouter.StopHealthChecks()
+	}
+	if memorySessionStore != nil {
+		memorySessionStore.StopJanitor()
+		stats := memorySessionStore.Stats()
+		logrus.Infof("Memory session store stopped: sessions=%d, expiredEvictions=%d, capacityEvictions=%d",
+			stats.Sessions, stats.ExpiredEvictions, stats.CapacityEvictions)
+	}
+	if redisClient != nil {
+		redis.DisconnectRedis(redisClient)
+	}
+	gorm.DisconnectPostgres(dbConGorm.Postgres)
+
 	if err != nil {
 		return err
 	}
@@ -138,3 +391,180 @@ func ServeHTTP() error {
 	logrus.Println("Listerning on port: ", configs.GetViper().App.Port)
 	return nil
 }
+
+// backendConfig - One entry of LMSTUDIO_BACKENDS
+type backendConfig struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"` // "lmstudio" (default) or "ollama"
+	BaseURL  string `json:"base_url"`
+	Model    string `json:"model"`
+	Weight   *int   `json:"weight"` // Defaults to 1
+}
+
+// parseBackends - Parses the JSON list of LLM backends
+// Entries without a base URL are skipped with a warning; invalid JSON disables routing.
+func parseBackends(value string) []backendConfig {
+	var backends []backendConfig
+	if value == "" {
+		return backends
+	}
+
+	var entries []backendConfig
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		logrus.Warnf("Ignoring invalid LLM backend list: %v", err)
+		return backends
+	}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry.BaseURL) == "" {
+			logrus.Warnf("Ignoring LLM backend without base_url: %q", entry.Name)
+			continue
+		}
+		if entry.Name == "" {
+			entry.Name = entry.BaseURL
+		}
+		backends = append(backends, entry)
+	}
+
+	return backends
+}
+
+// newBackends - Creates a client adapter per backend for the router
+// Timeouts and other settings not given per backend come from the LM Studio config.
+func newBackends(lmStudioConfig configs.LMStudio, backendConfigs []backendConfig) ([]routingAdapter.Backend, error) {
+	backends := make([]routingAdapter.Backend, 0, len(backendConfigs))
+	for _, backend := range backendConfigs {
+		clientConfig := lmStudioConfig
+		clientConfig.BaseURL = backend.BaseURL
+		clientConfig.Model = backend.Model
+
+		client, err := newLLMClient(backend.Provider, clientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("backend %s: %w", backend.Name, err)
+		}
+
+		weight := 1 // default weight
+		if backend.Weight != nil {
+			weight = *backend.Weight
+		}
+		backends = append(backends, routingAdapter.Backend{Name: backend.Name, Client: client, Weight: weight})
+	}
+
+	return backends, nil
+}
+
+// newLLMClient - Creates the client adapter for an LLM provider
+// An empty provider means LM Studio, or any other server with an OpenAI-compatible API.
+func newLLMClient(provider string, config configs.LMStudio) (output.LMStudioClient, error) {
+	switch providerName(provider) {
+	case "lmstudio":
+		return lmstudioAdapter.NewLMStudioClientAdapter(config)
+	case "ollama":
+		return ollamaAdapter.NewOllamaClientAdapter(config)
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q, expected lmstudio or ollama", provider)
+	}
+}
+
+// providerName - Normalizes an LLM provider name, defaulting to lmstudio
+func providerName(provider string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return "lmstudio"
+	}
+	return provider
+}
+
+// parseModelContextTokens - Parses per-model context windows in the form "model=tokens,model=tokens"
+// Invalid entries are skipped with a warning.
+func parseModelContextTokens(value string) map[string]int {
+	budgets := make(map[string]int)
+	if value == "" {
+		return budgets
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		model, tokens, found := strings.Cut(strings.TrimSpace(entry), "=")
+		n, err := strconv.Atoi(strings.TrimSpace(tokens))
+		if !found || model == "" || err != nil || n <= 0 {
+			logrus.Warnf("Ignoring invalid model context tokens entry: %q", entry)
+			continue
+		}
+		budgets[strings.TrimSpace(model)] = n
+	}
+
+	return budgets
+}
+
+// parseStickerReplies - Parses the sticker reply table in the form "keyword=packageID:stickerID,..."
+// The keyword "*" matches any sticker. Invalid entries are skipped with a warning.
+func parseStickerReplies(value string) []application.StickerReply {
+	var replies []application.StickerReply
+	if value == "" {
+		return replies
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		keyword, sticker, found := strings.Cut(strings.TrimSpace(entry), "=")
+		packageID, stickerID, hasIDs := strings.Cut(strings.TrimSpace(sticker), ":")
+		keyword = strings.TrimSpace(keyword)
+		if !found || !hasIDs || keyword == "" || packageID == "" || stickerID == "" {
+			logrus.Warnf("Ignoring invalid sticker reply entry: %q", entry)
+			continue
+		}
+		replies = append(replies, application.StickerReply{
+			Keyword:   keyword,
+			PackageID: packageID,
+			StickerID: stickerID,
+		})
+	}
+
+	return replies
+}
+
+// parsePersonas - Parses the persona catalog, a JSON object of name to system prompt
+// Personas are sorted by name. Entries without a name or prompt, or named like the default persona, are skipped with a warning.
+func parsePersonas(value string) []application.Persona {
+	var personas []application.Persona
+	if value == "" {
+		return personas
+	}
+
+	var catalog map[string]string
+	if err := json.Unmarshal([]byte(value), &catalog); err != nil {
+		logrus.Warnf("Ignoring invalid persona catalog: %v", err)
+		return personas
+	}
+
+	for name, prompt := range catalog {
+		name, prompt = strings.TrimSpace(name), strings.TrimSpace(prompt)
+		if name == "" || prompt == "" || strings.ContainsAny(name, " \t\n") || strings.EqualFold(name, "default") {
+			logrus.Warnf("Ignoring invalid persona entry: %q", name)
+			continue
+		}
+		personas = append(personas, application.Persona{Name: name, Prompt: prompt})
+	}
+	sort.Slice(personas, func(i, j int) bool { return personas[i].Name < personas[j].Name })
+
+	return personas
+}
+
+// parseUserIDs - Parses a comma-separated list of LINE user IDs, skipping empty entries
+func parseUserIDs(value string) []string {
+	var userIDs []string
+	for _, entry := range strings.Split(value, ",") {
+		if userID := strings.TrimSpace(entry); userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// stripPlaceholder returns value, or "" when it is still the config.yml placeholder
+// An empty environment variable is ignored by viper and leaves the placeholder in place.
+func stripPlaceholder(value, placeholder string) string {
+	if value == placeholder {
+		return ""
+	}
+	return value
+}